@@ -1,40 +1,322 @@
 package config
 
-import "github.com/PlanitarInc/go-config/decoders"
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	"github.com/PlanitarInc/go-config/reflectx"
+)
 
 type Flow struct {
-	decoders []decoders.Decoder
-	Config   interface{}
+	decoders   []decoders.Decoder
+	mapper     *reflectx.Mapper
+	defaults   interface{}
+	provenance map[string]Origin
+	skipped    []error
+	warnings   []error
+	status     []DecoderStatus
+	files      map[string]fileState
+	reloadFns  []func(old, new interface{})
+	errorFns   []func(err error)
+
+	beforeFns    []func(d decoders.Decoder, cfg interface{})
+	afterFns     []func(d decoders.Decoder, cfg interface{}, err error)
+	afterLoadFns []func(cfg interface{}) error
+	mutex        sync.Mutex
+	snapshot     atomic.Value
+
+	// Config is the config of the last successful load. Reading it while the
+	// flow loads is racy, see Snapshot.
+	Config interface{}
+	// WatchInterval is the interval Watch polls the files at. If it is zero,
+	// DefaultWatchInterval is used.
+	WatchInterval time.Duration
+	// DecoderTimeout limits the time every decoder may take to decode. If it
+	// is zero, decoders are only limited by the context given to LoadContext.
+	DecoderTimeout time.Duration
+	// Strict runs all the decoders in strict mode, rejecting unknown fields
+	// and duplicate keys in files. See decoders.WithStrict.
+	Strict bool
 }
 
+// NewFlow returns a flow running the decoders ds in order. defaults must be
+// a pointer to the config struct holding the default values; the flow keeps
+// a copy of it, later changes to defaults do not affect the flow. Flow.Config
+// is set to defaults until the first load.
 func NewFlow(defaults interface{}, ds ...decoders.Decoder) *Flow {
-	return &Flow{
+	m := reflectx.NewMapper("")
+	m.SetReduceFunc(reflectx.DelimiterKeyReducer("."))
+	setDefaults(defaults)
+	f := &Flow{
 		decoders: ds,
+		mapper:   m,
+		defaults: reflectx.Copy(defaults),
 		Config:   defaults,
 	}
+	f.snapshot.Store(reflectx.Copy(defaults))
+	return f
+}
+
+// Snapshot returns the config of the last successful load, or a copy of the
+// defaults before the first load. It is safe to call concurrently with loads
+// and never returns a partially decoded config.
+//
+// The snapshot is a copy of Flow.Config shared by all the callers until the
+// next load replaces it, so it must not be modified.
+func (f *Flow) Snapshot() interface{} {
+	return f.snapshot.Load()
+}
+
+// typeMap is like f.mapper.TypeMap, but returns no keys if t is not a struct
+// or a pointer to one. Configs of other types, e.g. maps, are decoded, but
+// have no keys to track, check or redact.
+func (f *Flow) typeMap(t reflect.Type) map[string][]int {
+	if reflectx.Deref(t).Kind() != reflect.Struct {
+		return map[string][]int{}
+	}
+	return f.mapper.TypeMap(reflectx.Deref(t))
+}
+
+// fieldMap is like f.mapper.FieldMapReadOnly, but returns no keys if v is not
+// a struct or a pointer to one, see typeMap.
+func (f *Flow) fieldMap(v reflect.Value) map[string]reflect.Value {
+	if reflectx.Deref(v.Type()).Kind() != reflect.Struct {
+		return map[string]reflect.Value{}
+	}
+	return f.mapper.FieldMapReadOnly(v)
+}
+
+// loadResult is the outcome of running the decoders of a flow on a config.
+type loadResult struct {
+	cfg  interface{}
+	errs Errors
+	// skipped lists the errors of the decoders that had nothing to decode
+	skipped []error
+	// warnings lists the errors the decoders reported without failing
+	warnings   []error
+	status     []DecoderStatus
+	provenance map[string]Origin
+	// cancelled is set if ctx was done before the load completed, cfg is
+	// only partly decoded then
+	cancelled bool
+}
+
+// loadHooks are the BeforeDecoder, AfterDecoder and AfterLoad hooks a load
+// calls.
+type loadHooks struct {
+	before    []func(d decoders.Decoder, cfg interface{})
+	after     []func(d decoders.Decoder, cfg interface{}, err error)
+	afterLoad []func(cfg interface{}) error
 }
 
-func (f Flow) load(failOnError bool) []error {
-	errs := []error{}
-	for _, d := range f.decoders {
-		if err := d.Decode(f.Config); err != nil {
+// hooks returns the hooks registered in the flow so far.
+func (f *Flow) hooks() loadHooks {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return loadHooks{
+		before:    f.beforeFns,
+		after:     f.afterFns,
+		afterLoad: f.afterLoadFns,
+	}
+}
+
+// loadInto runs the decoders of the flow on cfg, calling hooks, normalizes
+// the result, checks that the required keys were set and validates the
+// result.
+func (f *Flow) loadInto(ctx context.Context, cfg interface{},
+	failOnError bool, hooks loadHooks) loadResult {
+
+	r := loadResult{
+		cfg:        cfg,
+		errs:       Errors{},
+		skipped:    []error{},
+		warnings:   []error{},
+		status:     f.newStatus(),
+		provenance: map[string]Origin{},
+	}
+	if f.Strict {
+		ctx = decoders.WithStrict(ctx)
+	}
+	ctx = decoders.WithSecrets(ctx, secretFields(cfg))
+	ctx = decoders.WithIgnoredEnv(ctx, ProfileEnv)
+	for key := range f.typeMap(reflect.TypeOf(cfg)) {
+		r.provenance[key] = Origin{}
+	}
+
+	for i, d := range f.decoders {
+		st := &r.status[i]
+		if err := ctx.Err(); err != nil {
+			st.State = Failed
+			st.Err = &LoadError{Decoder: d, Err: err}
+			r.errs = append(r.errs, st.Err)
+			r.cancelled = true
+			return r
+		}
+
+		for _, fn := range hooks.before {
+			fn(d, cfg)
+		}
+
+		before := f.values(cfg)
+		start := time.Now()
+		set := [][]int{}
+		err := f.decode(decoders.WithSetFields(ctx, func(index []int) {
+			set = append(set, index)
+		}), d, cfg)
+		st.Duration = time.Since(start)
+		st.State = OK
+		if err != nil {
+			err = f.loadError(cfg, d, err)
+			st.Err = err
+		}
+		for _, fn := range hooks.after {
+			fn(d, cfg, err)
+		}
+
+		if err != nil {
+			var w *decoders.Warning
+			switch {
+			case errors.Is(err, decoders.ErrSkipped):
+				st.State = Skipped
+				r.skipped = append(r.skipped, err)
+				continue
+			case errors.As(err, &w):
+				st.State = Warning
+				r.warnings = append(r.warnings, err)
+			case failOnError:
+				st.State = Failed
+				r.errs = Errors{err}
+				return r
+			default:
+				st.State = Failed
+				r.errs = append(r.errs, err)
+			}
+		}
+		f.track(r.provenance, cfg, d, before, set)
+	}
+	if ctx.Err() != nil {
+		// The last decoder was interrupted
+		r.cancelled = true
+		return r
+	}
+
+	normalize(cfg)
+
+	if err := f.checkRequired(cfg, r.provenance); err != nil {
+		if failOnError {
+			r.errs = Errors{err}
+			return r
+		}
+		r.errs = append(r.errs, err)
+	}
+
+	verrs := append(f.validate(cfg), validateSections(cfg)...)
+	if len(verrs) > 0 {
+		if failOnError {
+			r.errs = Errors{verrs}
+			return r
+		}
+		r.errs = append(r.errs, verrs)
+	}
+
+	for _, fn := range hooks.afterLoad {
+		if err := fn(cfg); err != nil {
 			if failOnError {
-				return []error{err}
+				r.errs = Errors{err}
+				return r
 			}
-			errs = append(errs, err)
+			r.errs = append(r.errs, err)
 		}
 	}
-	return errs
+	return r
+}
+
+// decode runs d on cfg, limited by f.DecoderTimeout.
+func (f *Flow) decode(ctx context.Context, d decoders.Decoder,
+	cfg interface{}) error {
+
+	if f.DecoderTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.DecoderTimeout)
+		defer cancel()
+	}
+	return decoders.DecodeContext(ctx, d, cfg)
+}
+
+// load runs the decoders of the flow on a fresh copy of the defaults and
+// replaces Flow.Config with the result, unless the load was cancelled, or
+// failOnError is set and the load failed. It returns the previous config and
+// whether it was replaced.
+func (f *Flow) load(ctx context.Context, failOnError bool) (loadResult,
+	interface{}, bool) {
+
+	files := f.stat()
+	r := f.loadInto(ctx, reflectx.Copy(f.defaults), failOnError,
+		f.hooks())
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.status = r.status
+	if r.cancelled || failOnError && len(r.errs) > 0 {
+		return r, nil, false
+	}
+	old := f.Config
+	f.Config = r.cfg
+	f.snapshot.Store(reflectx.Copy(r.cfg))
+	f.provenance = r.provenance
+	f.skipped = r.skipped
+	f.warnings = r.warnings
+	f.files = files
+	return r, old, true
+}
+
+// Skipped returns the errors of the decoders that had nothing to decode
+// during the last load, e.g. optional files that do not exist. Such errors
+// wrap decoders.ErrSkipped and are not reported by Load.
+func (f *Flow) Skipped() []error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]error{}, f.skipped...)
 }
 
-func (f Flow) LoadFailIfError() error {
-	errs := f.load(true)
-	if len(errs) > 0 {
-		return errs[0]
+// Warnings returns the errors the decoders reported without failing during
+// the last load, e.g. unused env vars. Such errors wrap a decoders.Warning
+// and are not reported by Load.
+func (f *Flow) Warnings() []error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]error{}, f.warnings...)
+}
+
+// LoadFailIfError is like Load, but stops at the first error and returns
+// it. Flow.Config is only replaced if the load succeeds.
+func (f *Flow) LoadFailIfError() error {
+	r, _, _ := f.load(context.Background(), true)
+	if len(r.errs) > 0 {
+		return r.errs[0]
 	}
 	return nil
 }
 
-func (f Flow) Load() []error {
-	return f.load(false)
+// Load runs the decoders of the flow on a fresh copy of the defaults given
+// to NewFlow and replaces Flow.Config with the result. The keys none of the
+// decoders set hold the default values, even if an earlier load set them.
+// Flow.Config is replaced even if some of the decoders fail, the errors of
+// all of them are returned.
+func (f *Flow) Load() Errors {
+	return f.LoadContext(context.Background())
+}
+
+// LoadContext is like Load, but stops once ctx is done. Decoders that do not
+// implement decoders.ContextDecoder cannot be interrupted, ctx is checked
+// before each of them runs. A load stopped by ctx keeps Flow.Config, only the
+// status of the decoders is recorded.
+func (f *Flow) LoadContext(ctx context.Context) Errors {
+	r, _, _ := f.load(ctx, false)
+	return r.errs
 }
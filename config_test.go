@@ -1,8 +1,12 @@
 package config
 
 import (
+	"bytes"
 	"errors"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/PlanitarInc/go-config/decoders"
@@ -43,6 +47,8 @@ func TestEnvFlow(t *testing.T) {
 	f := NewFlow(&actual, decoders.NewEnvDecoder(""))
 	os.Setenv("NUMBER", "-987")
 	os.Setenv("STR2", "asd-987")
+	defer os.Unsetenv("NUMBER")
+	defer os.Unsetenv("STR2")
 	exp := def
 	exp.Number = -987
 	exp.Str2 = "asd-987"
@@ -119,6 +125,8 @@ func TestFlowOrder(t *testing.T) {
 
 	os.Setenv("NUMBER", "-987")
 	os.Setenv("STR2", "asd-987")
+	defer os.Unsetenv("NUMBER")
+	defer os.Unsetenv("STR2")
 
 	exp := def
 	exp.Number = -987
@@ -129,6 +137,120 @@ func TestFlowOrder(t *testing.T) {
 	Ω(f.Config).Should(Equal(&exp))
 }
 
+func TestOptionalFileFlow(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+		Flag   bool
+		Str1   string
+		Str2   string
+	}
+
+	def := Cfg{Number: -123, Flag: true, Str1: "qwe", Str2: "asd"}
+
+	actual := def
+	missing := decoders.NewOptionalYamlFileDecoder("missing.yml")
+	f := NewFlow(&actual,
+		decoders.NewOptionalYamlFileDecoder("test.yml"),
+		missing,
+	)
+	exp := def
+	exp.Number = 987654321
+	exp.Str1 = "aasd"
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Config).Should(Equal(&exp))
+	Ω(f.Skipped()).Should(HaveLen(1))
+	Ω(f.Skipped()[0]).Should(MatchError(decoders.ErrSkipped))
+	Ω(f.Skipped()[0].(*LoadError).Decoder).Should(Equal(missing))
+
+	Ω(f.Load()).Should(BeEmpty())
+	Ω(f.Skipped()).Should(HaveLen(1))
+}
+
+func TestLoadFreshCopy(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+		Str1   string
+	}
+
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	writeFile := func(s string) {
+		Ω(ioutil.WriteFile(filename, []byte(s), 0644)).Should(Succeed())
+	}
+	writeFile("number: 1\nstr1: one\n")
+
+	def := Cfg{Number: -1, Str1: "def"}
+	actual := def
+	f := NewFlow(&actual, decoders.NewYamlFileDecoder(filename))
+	Ω(f.Config).Should(BeIdenticalTo(&actual))
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Config).Should(Equal(&Cfg{Number: 1, Str1: "one"}))
+	Ω(actual).Should(Equal(def))
+
+	// A removed key reverts to the default value
+	first := f.Config
+	writeFile("number: 2\n")
+	Ω(f.Load()).Should(BeEmpty())
+	Ω(f.Config).Should(Equal(&Cfg{Number: 2, Str1: "def"}))
+	Ω(first).Should(Equal(&Cfg{Number: 1, Str1: "one"}))
+	Ω(actual).Should(Equal(def))
+
+	// A failed load keeps the current config
+	writeFile("number: [broken\n")
+	Ω(f.LoadFailIfError()).Should(HaveOccurred())
+	Ω(f.Config).Should(Equal(&Cfg{Number: 2, Str1: "def"}))
+}
+
+func TestStrictFlow(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+	}
+
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	Ω(ioutil.WriteFile(filename, []byte("nuber: 5\n"), 0644)).Should(Succeed())
+
+	actual := Cfg{}
+	f := NewFlow(&actual, decoders.NewYamlFileDecoder(filename))
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+
+	f.Strict = true
+	err := f.LoadFailIfError()
+	Ω(err).Should(MatchError(ContainSubstring(filename)))
+	Ω(err).Should(MatchError(ContainSubstring("line 1: field nuber not found")))
+}
+
+func TestWarnings(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+	}
+
+	os.Setenv("APP_NUMBER", "5")
+	os.Setenv("APP_NUBMER", "6")
+	defer os.Unsetenv("APP_NUMBER")
+	defer os.Unsetenv("APP_NUBMER")
+
+	actual := Cfg{}
+	ed := decoders.NewEnvDecoderOptions(decoders.EnvOptions{
+		Prefix: "APP_",
+		Unused: decoders.WarnUnused,
+	})
+	f := NewFlow(&actual, ed)
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Config).Should(Equal(&Cfg{Number: 5}))
+	Ω(f.Provenance()["Number"]).Should(Equal(Origin{Decoder: ed,
+		Source: "APP_NUMBER"}))
+	Ω(f.Warnings()).Should(HaveLen(1))
+	Ω(f.Warnings()[0]).Should(MatchError(
+		"unused env vars: APP_NUBMER (did you mean APP_NUMBER?)"))
+}
+
 type FailDecoder struct {
 	err error
 }
@@ -158,30 +280,167 @@ func TestErrorCollection(t *testing.T) {
 
 	cd = &CountDecoder{}
 	f = NewFlow(&act, cd)
-	Ω(f.Load()).Should(Equal([]error{}))
+	Ω(f.Load()).Should(Equal(Errors{}))
 	Ω(cd.cnt).Should(Equal(1))
 	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
 	Ω(cd.cnt).Should(Equal(2))
 
 	cd = &CountDecoder{}
 	f = NewFlow(&act, FailDecoder{e1}, cd)
-	Ω(f.Load()).Should(Equal([]error{e1}))
+	Ω(f.Load()).Should(Equal(Errors{
+		&LoadError{Decoder: FailDecoder{e1}, Err: e1},
+	}))
 	Ω(cd.cnt).Should(Equal(1))
-	Ω(f.LoadFailIfError()).Should(Equal(e1))
+	Ω(f.LoadFailIfError()).Should(Equal(
+		&LoadError{Decoder: FailDecoder{e1}, Err: e1},
+	))
 	Ω(cd.cnt).Should(Equal(1))
 
 	cd = &CountDecoder{}
 	f = NewFlow(&act, cd, &FailDecoder{e1}, cd, &FailDecoder{e2}, cd)
-	Ω(f.Load()).Should(Equal([]error{e1, e2}))
+	Ω(f.Load()).Should(Equal(Errors{
+		&LoadError{Decoder: &FailDecoder{e1}, Err: e1},
+		&LoadError{Decoder: &FailDecoder{e2}, Err: e2},
+	}))
 	Ω(cd.cnt).Should(Equal(3))
-	Ω(f.LoadFailIfError()).Should(Equal(e1))
+	Ω(f.LoadFailIfError()).Should(MatchError(e1))
 	Ω(cd.cnt).Should(Equal(4))
 
 	cd = &CountDecoder{}
 	f = NewFlow(&act, cd, FailDecoder{e1}, FailDecoder{e3}, FailDecoder{e2},
 		cd)
-	Ω(f.Load()).Should(Equal([]error{e1, e3, e2}))
+	errs := f.Load()
+	Ω(errs).Should(HaveLen(3))
+	Ω(errs).Should(MatchError("1; 3; 2"))
+	Ω(errors.Is(errs, e1)).Should(BeTrue())
+	Ω(errors.Is(errs, e2)).Should(BeTrue())
+	Ω(errors.Is(errs, e3)).Should(BeTrue())
 	Ω(cd.cnt).Should(Equal(2))
-	Ω(f.LoadFailIfError()).Should(Equal(e1))
+	Ω(f.LoadFailIfError()).Should(MatchError(e1))
 	Ω(cd.cnt).Should(Equal(3))
 }
+
+func TestLoadError(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Server struct {
+			Port int
+		}
+	}
+
+	os.Setenv("SERVER_PORT", "abc")
+	defer os.Unsetenv("SERVER_PORT")
+
+	actual := Cfg{}
+	ed := decoders.NewEnvDecoder("")
+	f := NewFlow(&actual, ed)
+	errs := f.Load()
+	Ω(errs).Should(HaveLen(1))
+	Ω(errs).Should(MatchError(
+		"env SERVER_PORT=abc: cannot parse as int for field Server.Port"))
+
+	var le *LoadError
+	Ω(errors.As(errs, &le)).Should(BeTrue())
+	Ω(le.Decoder).Should(Equal(ed))
+	Ω(le.Key).Should(Equal("SERVER_PORT"))
+	Ω(le.Field).Should(Equal("Server.Port"))
+	Ω(le.Value).Should(Equal("abc"))
+
+	var ve *decoders.ValueError
+	Ω(errors.As(errs, &ve)).Should(BeTrue())
+	Ω(ve.Type.Kind()).Should(Equal(reflect.Int))
+
+	os.Setenv("APP_SERVER_PORT", "abc")
+	defer os.Unsetenv("APP_SERVER_PORT")
+	f = NewFlow(&actual, decoders.NewEnvDecoderOptions(decoders.EnvOptions{
+		Prefix: "APP_",
+	}))
+	Ω(f.Load()).Should(MatchError(
+		"env APP_SERVER_PORT=abc: cannot parse as int for field Server.Port"))
+
+	filename := filepath.Join(t.TempDir(), "config.ini")
+	Ω(ioutil.WriteFile(filename, []byte("[server]\nport = abc\n"), 0644)).
+		Should(Succeed())
+	f = NewFlow(&actual, decoders.NewIniFileDecoder(filename))
+	Ω(f.Load()).Should(MatchError(filename + ": " +
+		"ini server.port=abc: cannot parse as int for field Server.Port"))
+
+	// The file tells apart the layers of a profile flow
+	local := filepath.Join(filepath.Dir(filename), "config.local.ini")
+	Ω(ioutil.WriteFile(filename, []byte("[server]\nport = 80\n"), 0644)).
+		Should(Succeed())
+	Ω(ioutil.WriteFile(local, []byte("[server]\nport = x\n"), 0644)).
+		Should(Succeed())
+	f = NewProfileFlow(&actual, filename, "")
+	Ω(f.Load()).Should(MatchError(local + ": " +
+		"ini server.port=x: cannot parse as int for field Server.Port"))
+}
+
+func TestSnapshot(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+		Str1   string
+	}
+
+	actual := Cfg{Number: -1}
+	f := NewFlow(&actual, decoders.NewYamlFileDecoder("test.yml"))
+	Ω(f.Snapshot()).Should(Equal(&Cfg{Number: -1}))
+	actual.Number = 5
+	Ω(f.Snapshot()).Should(Equal(&Cfg{Number: -1}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			cfg := f.Snapshot().(*Cfg)
+			if cfg.Number != -1 && cfg.Number != 987654321 ||
+				(cfg.Number == 987654321) != (cfg.Str1 == "aasd") {
+
+				t.Errorf("inconsistent snapshot: %+v", cfg)
+			}
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		Ω(f.Load()).Should(BeEmpty())
+	}
+	<-done
+
+	snap := f.Snapshot()
+	Ω(snap).Should(Equal(&Cfg{Number: 987654321, Str1: "aasd"}))
+	f.Config.(*Cfg).Number = 1
+	Ω(snap).Should(Equal(&Cfg{Number: 987654321, Str1: "aasd"}))
+	Ω(f.Snapshot()).Should(BeIdenticalTo(snap))
+
+	// A failed load keeps the snapshot
+	f = NewFlow(&actual, FailDecoder{errors.New("1")})
+	snap = f.Snapshot()
+	Ω(f.LoadFailIfError()).Should(HaveOccurred())
+	Ω(f.Snapshot()).Should(BeIdenticalTo(snap))
+}
+
+func TestNonStructFlow(t *testing.T) {
+	RegisterTestingT(t)
+
+	actual := map[string]interface{}{}
+	f := NewFlow(&actual, decoders.NewYamlFileDecoder("test.yml"))
+	Ω(f.Load()).Should(BeEmpty())
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Config).Should(Equal(&map[string]interface{}{
+		"number": 987654321,
+		"str1":   "aasd",
+	}))
+	Ω(f.Provenance()).Should(BeEmpty())
+
+	changes, errs := f.Diff()
+	Ω(errs).Should(BeEmpty())
+	Ω(changes).Should(BeEmpty())
+
+	var buf bytes.Buffer
+	Ω(f.Dump(FormatYAML, &buf)).Should(Succeed())
+	Ω(buf.String()).Should(Equal("number: 987654321\nstr1: aasd\n"))
+	buf.Reset()
+	Ω(f.Usage(&buf)).Should(Succeed())
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+type SlowDecoder struct {
+	delay time.Duration
+}
+
+func (d SlowDecoder) Decode(dst interface{}) error {
+	return d.DecodeContext(context.Background(), dst)
+}
+
+func (d SlowDecoder) DecodeContext(ctx context.Context, dst interface{}) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d.delay):
+		return nil
+	}
+}
+
+func TestLoadContext(t *testing.T) {
+	RegisterTestingT(t)
+
+	act := struct{}{}
+	var f *Flow
+	var cd *CountDecoder
+
+	cd = &CountDecoder{}
+	f = NewFlow(&act, cd, cd)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs := f.LoadContext(ctx)
+	Ω(errs).Should(Equal(Errors{
+		&LoadError{Decoder: cd, Err: context.Canceled},
+	}))
+	Ω(cd.cnt).Should(Equal(0))
+
+	// Per decoder timeout
+	cd = &CountDecoder{}
+	slow := SlowDecoder{time.Second}
+	f = NewFlow(&act, slow, cd)
+	f.DecoderTimeout = 10 * time.Millisecond
+	errs = f.LoadContext(context.Background())
+	Ω(errs).Should(Equal(Errors{
+		&LoadError{Decoder: slow, Err: context.DeadlineExceeded},
+	}))
+	Ω(cd.cnt).Should(Equal(1))
+
+	// Overall timeout
+	cd = &CountDecoder{}
+	fast := SlowDecoder{time.Millisecond}
+	f = NewFlow(&act, fast, slow, cd)
+	ctx, cancel = context.WithTimeout(context.Background(),
+		20*time.Millisecond)
+	defer cancel()
+	errs = f.LoadContext(ctx)
+	Ω(errs).Should(HaveLen(2))
+	Ω(errors.Is(errs[0], context.DeadlineExceeded)).Should(BeTrue())
+	Ω(errs[1]).Should(Equal(
+		&LoadError{Decoder: cd, Err: context.DeadlineExceeded},
+	))
+	Ω(cd.cnt).Should(Equal(0))
+
+	// A cancelled load keeps the config of the last load
+	type Cfg struct {
+		Number int `validate:"min=1"`
+		Str1   string
+	}
+	sd := decoders.NewStructDecoder(Cfg{Number: 5, Str1: "a"}, "", "")
+	slow = SlowDecoder{100 * time.Millisecond}
+	for _, ds := range [][]decoders.Decoder{{sd, cd}, {sd, slow}} {
+		f = NewFlow(&Cfg{}, ds...)
+		Ω(f.Load()).Should(BeEmpty())
+		exp := &Cfg{Number: 5, Str1: "a"}
+		Ω(f.Config).Should(Equal(exp))
+
+		ctx, cancel = context.WithTimeout(context.Background(),
+			10*time.Millisecond)
+		if ds[1] == cd {
+			cancel()
+		}
+		errs = f.LoadContext(ctx)
+		cancel()
+		Ω(errs).Should(HaveLen(1))
+		Ω(f.Config).Should(Equal(exp))
+		Ω(f.Snapshot()).Should(Equal(exp))
+		if ds[1] == cd {
+			Ω(f.Status()[0].State).Should(Equal(Failed))
+		} else {
+			Ω(f.Status()[0].State).Should(Equal(OK))
+			Ω(f.Status()[1].State).Should(Equal(Failed))
+		}
+	}
+}
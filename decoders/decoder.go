@@ -1,8 +1,13 @@
 package decoders
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"os"
 	"reflect"
+	"sort"
 
 	"github.com/PlanitarInc/go-config/reflectx"
 )
@@ -11,6 +16,35 @@ type Decoder interface {
 	Decode(dst interface{}) error
 }
 
+// ContextDecoder is implemented by decoders that can be cancelled.
+type ContextDecoder interface {
+	DecodeContext(ctx context.Context, dst interface{}) error
+}
+
+// DecodeContext runs d.DecodeContext if d implements ContextDecoder, and
+// falls back to d.Decode otherwise. In the latter case ctx is only checked
+// before d runs.
+func DecodeContext(ctx context.Context, d Decoder, dst interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if cd, ok := d.(ContextDecoder); ok {
+		return cd.DecodeContext(ctx, dst)
+	}
+	return d.Decode(dst)
+}
+
+// Sourcer is implemented by decoders that can name the raw source they read
+// the field at index of dst from, e.g. an env var name or a file path.
+type Sourcer interface {
+	Source(dst interface{}, index []int) string
+}
+
+// Filer is implemented by decoders that read files.
+type Filer interface {
+	Files() []string
+}
+
 type KVStore interface {
 	DecodeKey(key string, dst interface{}) error
 	Tagname() string
@@ -24,16 +58,63 @@ type kvwrapper struct {
 }
 
 func (d kvwrapper) Decode(dst interface{}) error {
-	m := d.mapper.FieldMap(reflect.ValueOf(dst))
-	for key, field := range m {
-		pf := field.Addr().Interface()
-		if err := d.store.DecodeKey(key, pf); err != nil {
+	return d.DecodeContext(context.Background(), dst)
+}
+
+func (d kvwrapper) DecodeContext(ctx context.Context, dst interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(dst))
+	tm := d.mapper.TypeMap(v.Type())
+
+	keys := make([]string, 0, len(tm))
+	for key := range tm {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kc, _ := d.store.(KeyChecker)
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
+		pf := reflectx.FieldByIndexes(v, tm[key]).Addr().Interface()
+		if err := d.store.DecodeKey(key, pf); err != nil {
+			return &KeyError{Key: key, Index: tm[key], Err: err}
+		}
+		if kc != nil && kc.HasKey(key) {
+			SetField(ctx, tm[key])
+		}
 	}
 	return nil
 }
 
+// Source returns the key the field at index of dst is mapped to.
+func (d kvwrapper) Source(dst interface{}, index []int) string {
+	t := reflectx.Deref(reflect.TypeOf(dst))
+	for key, idx := range d.mapper.TypeMap(t) {
+		if equalIndex(idx, index) {
+			return key
+		}
+	}
+	return ""
+}
+
+// Name returns the name of the store, if it implements Namer.
+func (d kvwrapper) Name() string {
+	if n, ok := d.store.(Namer); ok {
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", d.store)
+}
+
+// Kind returns the kind of the store, if it implements Kinder, and its name
+// otherwise.
+func (d kvwrapper) Kind() string {
+	if k, ok := d.store.(Kinder); ok {
+		return k.Kind()
+	}
+	return d.Name()
+}
+
 func KVWrapper(s KVStore) Decoder {
 	m := reflectx.NewMapperFunc(s.Tagname(), s.MapFunc())
 	m.SetReduceFunc(s.ReduceFunc())
@@ -47,19 +128,109 @@ type Unmarshaller interface {
 type fileunmarshaller struct {
 	filename string
 	u        Unmarshaller
+	optional bool
 }
 
 func (f fileunmarshaller) Decode(dst interface{}) error {
+	return f.DecodeContext(context.Background(), dst)
+}
+
+func (f fileunmarshaller) DecodeContext(ctx context.Context,
+	dst interface{}) error {
+
 	bs, err := ioutil.ReadFile(f.filename)
+	if f.optional && errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s does not exist", ErrSkipped, f.filename)
+	}
 	if err != nil {
 		return err
 	}
-	return f.u.Unmarshall(bs, dst)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if su, ok := f.u.(StrictUnmarshaller); ok && IsStrict(ctx) {
+		err = su.UnmarshallStrict(bs, dst)
+	} else {
+		err = f.u.Unmarshall(bs, dst)
+	}
+	if err != nil {
+		err = redactSecrets(ctx, f.u, bs, dst, err)
+		return fmt.Errorf("%s: %w", f.filename, err)
+	}
+
+	if fu, ok := f.u.(FieldsUnmarshaller); ok && recordsFields(ctx) {
+		fields, err := fu.Fields(bs, dst)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.filename, err)
+		}
+		for _, index := range fields {
+			SetField(ctx, index)
+		}
+	}
+	return nil
+}
+
+// Source returns the name of the file, all the fields come from it.
+func (f fileunmarshaller) Source(dst interface{}, index []int) string {
+	return f.filename
+}
+
+// Name returns the format and the name of the file, e.g. "yaml file
+// app.yaml". The format is the name of the unmarshaller, if it implements
+// Namer.
+func (f fileunmarshaller) Name() string {
+	s := "file " + f.filename
+	if n, ok := f.u.(Namer); ok {
+		s = n.Name() + " " + s
+	}
+	if f.optional {
+		s = "optional " + s
+	}
+	return s
 }
 
+// Kind returns the format of the file, i.e. the name of the unmarshaller if
+// it implements Namer, and "file" otherwise.
+func (f fileunmarshaller) Kind() string {
+	if n, ok := f.u.(Namer); ok {
+		return n.Name()
+	}
+	return "file"
+}
+
+func (f fileunmarshaller) Files() []string {
+	return []string{f.filename}
+}
+
+// NewFileUnmarshaller returns a decoder that unmarshalls the content of the
+// file using u. The file is required, decoding fails if it does not exist.
 func NewFileUnmarshaller(filename string, u Unmarshaller) Decoder {
 	return &fileunmarshaller{
 		filename: filename,
 		u:        u,
 	}
 }
+
+// NewOptionalFileUnmarshaller is like NewFileUnmarshaller, but the file is
+// optional: if it does not exist, decoding returns an error wrapping
+// ErrSkipped.
+func NewOptionalFileUnmarshaller(filename string, u Unmarshaller) Decoder {
+	return &fileunmarshaller{
+		filename: filename,
+		u:        u,
+		optional: true,
+	}
+}
+
+func equalIndex(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
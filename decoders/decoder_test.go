@@ -0,0 +1,59 @@
+package decoders
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDecodeContext(t *testing.T) {
+	RegisterTestingT(t)
+
+	dst := struct{ A int }{}
+	os.Setenv("A", "5")
+	defer os.Unsetenv("A")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	d := NewEnvDecoder("")
+	Ω(DecodeContext(ctx, d, &dst)).Should(Equal(context.Canceled))
+	Ω(d.(ContextDecoder).DecodeContext(ctx, &dst)).
+		Should(Equal(context.Canceled))
+	Ω(dst.A).Should(Equal(0))
+
+	fd := NewYamlFileDecoder("./config_test.yaml")
+	Ω(DecodeContext(ctx, fd, &dst)).Should(Equal(context.Canceled))
+
+	Ω(DecodeContext(context.Background(), d, &dst)).Should(Succeed())
+	Ω(dst.A).Should(Equal(5))
+}
+
+func TestOptionalFileDecoder(t *testing.T) {
+	RegisterTestingT(t)
+
+	var v map[string]interface{}
+
+	err := NewYamlFileDecoder("./missing.yaml").Decode(&v)
+	Ω(err).Should(HaveOccurred())
+	Ω(errors.Is(err, ErrSkipped)).Should(BeFalse())
+
+	err = NewOptionalYamlFileDecoder("./missing.yaml").Decode(&v)
+	Ω(err).Should(MatchError("skipped: ./missing.yaml does not exist"))
+	Ω(errors.Is(err, ErrSkipped)).Should(BeTrue())
+
+	err = NewOptionalJsonFileDecoder("./missing.json").Decode(&v)
+	Ω(errors.Is(err, ErrSkipped)).Should(BeTrue())
+
+	// An existing optional file must be valid
+	err = NewOptionalJsonFileDecoder("./config_test.yaml").Decode(&v)
+	Ω(err).Should(HaveOccurred())
+	Ω(errors.Is(err, ErrSkipped)).Should(BeFalse())
+
+	Ω(NewOptionalYamlFileDecoder("./config_test.yaml").Decode(&v)).
+		Should(Succeed())
+	Ω(v).Should(HaveKey("simple"))
+}
@@ -0,0 +1,49 @@
+package decoders
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+type defaultsDecoder struct {
+	mapper *reflectx.Mapper
+}
+
+// Decode sets every field of dst that has a `default` tag to the value of the
+// tag, parsed the same way env var values are.
+func (d defaultsDecoder) Decode(dst interface{}) error {
+	return d.DecodeContext(context.Background(), dst)
+}
+
+func (d defaultsDecoder) DecodeContext(ctx context.Context,
+	dst interface{}) error {
+
+	v := reflect.Indirect(reflect.ValueOf(dst))
+	for key, index := range d.mapper.TypeMap(v.Type()) {
+		tag, ok := v.Type().FieldByIndex(index).Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+		field := reflectx.FieldByIndexes(v, index)
+		if err := parseValue(tag, field.Addr().Interface()); err != nil {
+			return &KeyError{Key: key, Index: index, Err: err}
+		}
+		SetField(ctx, index)
+	}
+	return nil
+}
+
+func (d defaultsDecoder) Name() string {
+	return "defaults"
+}
+
+// NewDefaultsDecoder returns a decoder that sets fields to the default values
+// declared by their `default` tags, e.g. `default:"30s"` or `default:"[a,b]"`.
+// It is usually the first decoder of a flow.
+func NewDefaultsDecoder() Decoder {
+	m := reflectx.NewMapper("")
+	m.SetReduceFunc(reflectx.DelimiterKeyReducer("."))
+	return &defaultsDecoder{mapper: m}
+}
@@ -0,0 +1,46 @@
+package decoders
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDefaultsDecoder(t *testing.T) {
+	RegisterTestingT(t)
+
+	type B struct {
+		N int `default:"7"`
+	}
+	dst := struct {
+		B
+		Timeout time.Duration `default:"30s"`
+		Hosts   []string      `default:"[a,b]"`
+		Flag    bool          `default:"true"`
+		Name    string        `default:""`
+		Keep    string
+		Ptr     *int `default:"3"`
+		Nested  struct {
+			F float64 `default:"1.5"`
+		}
+	}{Name: "overridden", Keep: "kept"}
+
+	d := NewDefaultsDecoder()
+	Ω(d.Decode(&dst)).Should(Succeed())
+	Ω(dst.B.N).Should(Equal(7))
+	Ω(dst.Timeout).Should(Equal(30 * time.Second))
+	Ω(dst.Hosts).Should(Equal([]string{"a", "b"}))
+	Ω(dst.Flag).Should(BeTrue())
+	Ω(dst.Name).Should(Equal("overridden"))
+	Ω(dst.Keep).Should(Equal("kept"))
+	Ω(*dst.Ptr).Should(Equal(3))
+	Ω(dst.Nested.F).Should(Equal(1.5))
+
+	bad := struct {
+		Nested struct {
+			N int `default:"abc"`
+		}
+	}{}
+	Ω(d.Decode(&bad)).Should(MatchError("Nested.N: cannot parse as int"))
+}
@@ -0,0 +1,320 @@
+package decoders
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// dotenvStore is the KVStore of the variables of a parsed .env file, mapped
+// the same way env vars are.
+type dotenvStore struct {
+	envDecoder
+	values map[string]string
+}
+
+// DecodeKey sets string fields to the value as is, since quotes and escapes
+// are handled by the .env syntax, see parseRawValue.
+func (s dotenvStore) DecodeKey(key string, dst interface{}) error {
+	if val, ok := s.values[key]; ok && val != "" {
+		return parseRawValue(val, dst)
+	}
+	return nil
+}
+
+func (s dotenvStore) HasKey(key string) bool {
+	return s.values[key] != ""
+}
+
+// parseRawValue is like parseValue, but sets string fields to val as is, for
+// values that were already unquoted, e.g. by a shell.
+func parseRawValue(val string, dst interface{}) error {
+	if v := reflect.ValueOf(dst).Elem(); v.Kind() == reflect.String {
+		v.SetString(val)
+		return nil
+	}
+	return parseValue(val, dst)
+}
+
+type dotenvUnmarshaller struct {
+	opts EnvOptions
+}
+
+func (u dotenvUnmarshaller) Unmarshall(bs []byte, dst interface{}) error {
+	d, err := u.decoder(bs)
+	if err != nil {
+		return err
+	}
+	return d.Decode(dst)
+}
+
+func (u dotenvUnmarshaller) Fields(bs []byte,
+	dst interface{}) ([][]int, error) {
+
+	d, err := u.decoder(bs)
+	if err != nil {
+		return nil, err
+	}
+	return d.fields(dst), nil
+}
+
+// UnmarshallStrict rejects variables set more than once and variables
+// starting with the prefix that do not match any field.
+func (u dotenvUnmarshaller) UnmarshallStrict(bs []byte,
+	dst interface{}) error {
+
+	values, err := parseDotenv(string(bs), true)
+	if err != nil {
+		return err
+	}
+
+	d := u.wrap(values)
+	keys := d.mapper.TypeMap(reflectx.Deref(reflect.TypeOf(dst)))
+	unknown := []string{}
+	for name := range values {
+		_, ok := keys[name]
+		if !ok && strings.HasPrefix(name, u.opts.Prefix) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown fields: %s", strings.Join(unknown, ", "))
+	}
+	return d.Decode(dst)
+}
+
+// decoder returns the decoder of the variables of the .env file bs.
+func (u dotenvUnmarshaller) decoder(bs []byte) (*kvwrapper, error) {
+	values, err := parseDotenv(string(bs), false)
+	if err != nil {
+		return nil, err
+	}
+	return u.wrap(values), nil
+}
+
+// wrap returns the decoder of the parsed variables values.
+func (u dotenvUnmarshaller) wrap(values map[string]string) *kvwrapper {
+	return KVWrapper(&dotenvStore{
+		envDecoder: envDecoder{tagname: u.opts.Tagname, prefix: u.opts.Prefix},
+		values:     values,
+	}).(*kvwrapper)
+}
+
+func (u dotenvUnmarshaller) Name() string {
+	return "dotenv"
+}
+
+// parseDotenv parses the content of a .env file into a map of variables. If a
+// variable is set more than once, the last value wins, unless failOnDup is
+// set.
+func parseDotenv(s string, failOnDup bool) (map[string]string, error) {
+	p := dotenvParser{s: s, line: 1}
+	values := map[string]string{}
+	for {
+		p.skipBlank()
+		if p.done() {
+			return values, nil
+		}
+		if p.peek() == '#' {
+			p.skipLine()
+			continue
+		}
+
+		line := p.line
+		name := p.name()
+		if name == "export" && !p.done() &&
+			(p.peek() == ' ' || p.peek() == '\t') {
+
+			p.skipSpaces()
+			name = p.name()
+		}
+		if name == "" {
+			return nil, fmt.Errorf("line %d: expected variable name", line)
+		}
+		p.skipSpaces()
+		if p.done() || p.peek() != '=' {
+			return nil, fmt.Errorf("line %d: expected = after %s", line, name)
+		}
+		p.pos++
+		p.skipSpaces()
+
+		val, err := p.value()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", line, name, err)
+		}
+		if _, ok := values[name]; ok && failOnDup {
+			return nil, fmt.Errorf("line %d: variable %s already set", line,
+				name)
+		}
+		values[name] = val
+	}
+}
+
+type dotenvParser struct {
+	s    string
+	pos  int
+	line int
+}
+
+func (p *dotenvParser) done() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *dotenvParser) peek() byte {
+	return p.s[p.pos]
+}
+
+// next returns the next byte, counting lines.
+func (p *dotenvParser) next() byte {
+	c := p.s[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *dotenvParser) skipSpaces() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) skipBlank() {
+	for !p.done() && strings.IndexByte(" \t\r\n", p.peek()) >= 0 {
+		p.next()
+	}
+}
+
+func (p *dotenvParser) skipLine() {
+	for !p.done() && p.next() != '\n' {
+	}
+}
+
+func (p *dotenvParser) name() string {
+	start := p.pos
+	for !p.done() {
+		c := p.peek()
+		if c != '_' && c != '.' && !('a' <= c && c <= 'z') &&
+			!('A' <= c && c <= 'Z') && !('0' <= c && c <= '9' && p.pos > start) {
+
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// value reads the value following the =, up to the end of the line or, for
+// quoted values, the closing quote.
+func (p *dotenvParser) value() (string, error) {
+	if p.done() {
+		return "", nil
+	}
+
+	var val string
+	switch p.peek() {
+	case '\'':
+		p.next()
+		start := p.pos
+		for !p.done() && p.peek() != '\'' {
+			p.next()
+		}
+		if p.done() {
+			return "", fmt.Errorf("unterminated single-quoted value")
+		}
+		val = p.s[start:p.pos]
+		p.next()
+	case '"':
+		p.next()
+		var err error
+		if val, err = p.doubleQuoted(); err != nil {
+			return "", err
+		}
+	default:
+		start := p.pos
+		for !p.done() && p.peek() != '\n' {
+			if p.peek() == '#' && (p.s[p.pos-1] == ' ' || p.s[p.pos-1] == '\t') {
+				break
+			}
+			p.pos++
+		}
+		val = strings.TrimSpace(p.s[start:p.pos])
+		p.skipLine()
+		return val, nil
+	}
+
+	// Only a comment may follow a quoted value
+	p.skipSpaces()
+	if !p.done() && p.peek() != '\n' && p.peek() != '\r' && p.peek() != '#' {
+		return "", fmt.Errorf("unexpected %q after quoted value", p.peek())
+	}
+	p.skipLine()
+	return val, nil
+}
+
+// doubleQuoted reads a double-quoted value up to the closing quote, which
+// follows the opening one. It expands the escapes \n, \r, \t, \", \\ and
+// \$; a backslash at the end of a line joins it with the next one.
+func (p *dotenvParser) doubleQuoted() (string, error) {
+	var b strings.Builder
+	for {
+		if p.done() {
+			return "", fmt.Errorf("unterminated double-quoted value")
+		}
+		c := p.next()
+		if c == '"' {
+			return b.String(), nil
+		}
+		if c != '\\' || p.done() {
+			b.WriteByte(c)
+			continue
+		}
+		switch e := p.next(); e {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '"', '\\', '$':
+			b.WriteByte(e)
+		case '\n':
+		default:
+			b.WriteByte(c)
+			b.WriteByte(e)
+		}
+	}
+}
+
+// NewDotenvFileDecoder returns a decoder of the required .env file. The
+// variables are mapped to fields the same way NewEnvDecoder("env") maps env
+// vars, without setting them in the process environment.
+//
+// The file holds NAME=value lines, optionally prefixed with export. Values
+// may be single-quoted, taken literally, or double-quoted, with escapes such
+// as \n expanded; quoted values may span multiple lines. Unquoted values end
+// at a # preceded by a space, which starts a comment. Variables are not
+// expanded.
+func NewDotenvFileDecoder(filename string) Decoder {
+	return NewDotenvFileDecoderOptions(filename, EnvOptions{Tagname: "env"})
+}
+
+// NewDotenvFileDecoderOptions is like NewDotenvFileDecoder, but maps the
+// variables the same way NewEnvDecoderOptions(opts) does. opts.Unused is
+// ignored.
+func NewDotenvFileDecoderOptions(filename string, opts EnvOptions) Decoder {
+	return NewFileUnmarshaller(filename, &dotenvUnmarshaller{opts: opts})
+}
+
+// NewOptionalDotenvFileDecoder returns a decoder of the optional .env file: if
+// the file does not exist, decoding returns an error wrapping ErrSkipped.
+func NewOptionalDotenvFileDecoder(filename string) Decoder {
+	return NewOptionalFileUnmarshaller(filename, &dotenvUnmarshaller{
+		opts: EnvOptions{Tagname: "env"},
+	})
+}
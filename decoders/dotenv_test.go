@@ -0,0 +1,127 @@
+package decoders
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseDotenv(t *testing.T) {
+	RegisterTestingT(t)
+
+	values, err := parseDotenv(`
+# a comment
+PLAIN=some value # trailing comment
+export EXPORTED = 1
+HASH=a#b
+EMPTY=
+SINGLE='single $X \n # not a comment'
+DOUBLE="tab\there \"quoted\" \\ \$X" # comment
+MULTI="first
+second"
+MULTI_SINGLE='first
+second'
+JOINED="first \
+second"
+CRLF=value`+"\r\n"+`
+`, false)
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(values).Should(Equal(map[string]string{
+		"PLAIN":        "some value",
+		"EXPORTED":     "1",
+		"HASH":         "a#b",
+		"EMPTY":        "",
+		"SINGLE":       `single $X \n # not a comment`,
+		"DOUBLE":       "tab\there \"quoted\" \\ $X",
+		"MULTI":        "first\nsecond",
+		"MULTI_SINGLE": "first\nsecond",
+		"JOINED":       "first second",
+		"CRLF":         "value",
+	}))
+
+	for s, msg := range map[string]string{
+		"A=1\n=2":          "line 2: expected variable name",
+		"A=1\nB 2":         "line 2: expected = after B",
+		"A='1\n2":          "line 1: A: unterminated single-quoted value",
+		"A=\"1\n2":         "line 1: A: unterminated double-quoted value",
+		"A=1\nB=\"2\" 3\n": `line 2: B: unexpected '3' after quoted value`,
+		"export":           "line 1: expected = after export",
+		"FOO=1\nexport":    "line 2: expected = after export",
+	} {
+		_, err := parseDotenv(s, false)
+		Ω(err).Should(MatchError(msg), s)
+	}
+}
+
+func TestNewDotenvFileDecoder(t *testing.T) {
+	RegisterTestingT(t)
+
+	os.Setenv("NUM", "5")
+	defer os.Unsetenv("NUM")
+
+	filename := filepath.Join(t.TempDir(), ".env")
+	Ω(ioutil.WriteFile(filename, []byte(`
+NUM=1
+STR="multi
+line: value"
+ARR=[a, b]
+TIMEOUT=30s
+NESTED_ONE_TWO=2
+LIST="[x, y]"
+APP_NUM=2
+`), 0644)).Should(Succeed())
+
+	type Cfg struct {
+		Num     int
+		Str     string
+		Arr     []string
+		Timeout time.Duration
+		Nested  struct {
+			One struct {
+				Two int
+			}
+		}
+		Items []string `env:"LIST"`
+	}
+
+	var v Cfg
+	d := NewDotenvFileDecoder(filename)
+	Ω(d.Decode(&v)).Should(Succeed())
+	Ω(v.Num).Should(Equal(1))
+	Ω(v.Str).Should(Equal("multi\nline: value"))
+	Ω(v.Arr).Should(Equal([]string{"a", "b"}))
+	Ω(v.Timeout).Should(Equal(30 * time.Second))
+	Ω(v.Nested.One.Two).Should(Equal(2))
+	Ω(v.Items).Should(Equal([]string{"x", "y"}))
+	Ω(os.Getenv("NUM")).Should(Equal("5"))
+	Ω(os.Getenv("STR")).Should(BeEmpty())
+	Ω(Name(d)).Should(Equal("dotenv file " + filename))
+
+	v = Cfg{}
+	d = NewDotenvFileDecoderOptions(filename, EnvOptions{Prefix: "APP_"})
+	Ω(d.Decode(&v)).Should(Succeed())
+	Ω(v).Should(Equal(Cfg{Num: 2}))
+
+	// Strict mode only looks at the variables starting with the prefix
+	Ω(Strict(d).Decode(&v)).Should(Succeed())
+	Ω(Strict(NewDotenvFileDecoder(filename)).Decode(&v)).Should(MatchError(
+		filename + ": unknown fields: APP_NUM"))
+
+	Ω(ioutil.WriteFile(filename, []byte("NUM=1\nSTR=a\nNUM=2\n"), 0644)).
+		Should(Succeed())
+	Ω(NewDotenvFileDecoder(filename).Decode(&v)).Should(Succeed())
+	Ω(v.Num).Should(Equal(2))
+	Ω(Strict(NewDotenvFileDecoder(filename)).Decode(&v)).Should(MatchError(
+		filename + ": line 3: variable NUM already set"))
+
+	Ω(ioutil.WriteFile(filename, []byte("NUM=abc\n"), 0644)).Should(Succeed())
+	Ω(NewDotenvFileDecoder(filename).Decode(&v)).Should(MatchError(
+		filename + ": NUM: cannot parse as int"))
+
+	d = NewOptionalDotenvFileDecoder(filepath.Join(t.TempDir(), ".env"))
+	Ω(d.Decode(&v)).Should(MatchError(ErrSkipped))
+}
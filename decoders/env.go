@@ -1,25 +1,83 @@
 package decoders
 
 import (
+	"context"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/PlanitarInc/go-config/reflectx"
 	"gopkg.in/yaml.v2"
 )
 
+// UnusedPolicy controls how an env decoder reports unused env vars, i.e. the
+// env vars starting with its prefix that do not match any field.
+type UnusedPolicy int
+
+const (
+	// IgnoreUnused does not look for unused env vars.
+	IgnoreUnused UnusedPolicy = iota
+	// WarnUnused reports unused env vars as a Warning.
+	WarnUnused
+	// FailUnused fails decoding if there are unused env vars.
+	FailUnused
+)
+
+// EnvOptions configures the env decoders created by NewEnvDecoderOptions.
+type EnvOptions struct {
+	// Tagname is the struct tag overriding the env var names of fields.
+	Tagname string
+	// Prefix is prepended to all the env var names, e.g. "APP_".
+	Prefix string
+	// Unused controls how unused env vars are reported. Unused env vars are
+	// only looked for if Prefix is set.
+	Unused UnusedPolicy
+	// Ignore lists the env vars that are never reported as unused, e.g. the
+	// ones read by the application itself.
+	Ignore []string
+}
+
 type envDecoder struct {
 	tagname string
+	prefix  string
 }
 
 func (s envDecoder) DecodeKey(key string, dst interface{}) error {
 	if val := os.Getenv(key); val != "" {
-		// Let the yaml decoder do the hard work
-		return yaml.Unmarshal([]byte(val), dst)
+		return parseValue(val, dst)
 	}
 	return nil
 }
 
+// parseValue parses a plain text value, e.g. of an env var, into dst.
+func parseValue(val string, dst interface{}) error {
+	// Let the yaml decoder do the hard work
+	if err := yaml.Unmarshal([]byte(val), dst); err != nil {
+		return &ValueError{
+			Value: val,
+			Type:  reflect.TypeOf(dst).Elem(),
+			Err:   err,
+		}
+	}
+	return nil
+}
+
+func (s envDecoder) HasKey(key string) bool {
+	return os.Getenv(key) != ""
+}
+
+func (s envDecoder) Kind() string {
+	return "env"
+}
+
+func (s envDecoder) Name() string {
+	if s.prefix == "" {
+		return "env"
+	}
+	return "env prefix " + s.prefix
+}
+
 func (s envDecoder) Tagname() string {
 	return s.tagname
 }
@@ -29,9 +87,156 @@ func (s envDecoder) MapFunc() func(string) string {
 }
 
 func (s envDecoder) ReduceFunc() func(string, string) string {
-	return reflectx.DelimiterKeyReducer("_")
+	reduce := reflectx.DelimiterKeyReducer("_")
+	return func(ns, name string) string {
+		if ns == "" {
+			return s.prefix + name
+		}
+		return reduce(ns, name)
+	}
+}
+
+// envwrapper is a KVWrapper of an envDecoder that looks for unused env vars.
+type envwrapper struct {
+	*kvwrapper
+	opts EnvOptions
+}
+
+func (d envwrapper) Decode(dst interface{}) error {
+	return d.DecodeContext(context.Background(), dst)
+}
+
+func (d envwrapper) DecodeContext(ctx context.Context, dst interface{}) error {
+	if err := d.kvwrapper.DecodeContext(ctx, dst); err != nil {
+		return err
+	}
+	if d.opts.Unused == IgnoreUnused || d.opts.Prefix == "" {
+		return nil
+	}
+
+	unused := d.unused(dst, ignoredEnv(ctx))
+	if len(unused) == 0 {
+		return nil
+	}
+	err := &UnusedEnvError{Vars: unused}
+	if d.opts.Unused == WarnUnused {
+		return &Warning{Err: err}
+	}
+	return err
+}
+
+// unused returns the env vars starting with the prefix that do not match any
+// field of dst, sorted by name. The env vars of opts.Ignore and ignore are
+// left out.
+func (d envwrapper) unused(dst interface{}, ignore []string) []UnusedEnv {
+	keys := d.mapper.TypeMap(reflectx.Deref(reflect.TypeOf(dst)))
+
+	names := []string{}
+	for _, kv := range os.Environ() {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if _, ok := keys[name]; ok || !strings.HasPrefix(name, d.opts.Prefix) ||
+			contains(d.opts.Ignore, name) || contains(ignore, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r := make([]UnusedEnv, len(names))
+	for i, name := range names {
+		r[i] = UnusedEnv{Name: name, Suggestion: suggest(name, keys)}
+	}
+	return r
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+type ignoredEnvKey struct{}
+
+// WithIgnoredEnv returns a copy of ctx that makes the env decoders running
+// with it never report the env vars names as unused, in addition to the ones
+// of their EnvOptions.Ignore.
+func WithIgnoredEnv(ctx context.Context, names ...string) context.Context {
+	names = append(append([]string{}, ignoredEnv(ctx)...), names...)
+	return context.WithValue(ctx, ignoredEnvKey{}, names)
+}
+
+// ignoredEnv returns the env vars ctx ignores, see WithIgnoredEnv.
+func ignoredEnv(ctx context.Context) []string {
+	names, _ := ctx.Value(ignoredEnvKey{}).([]string)
+	return names
+}
+
+// suggest returns the key closest to name, if any is close enough to be a
+// typo of name.
+func suggest(name string, keys map[string][]int) string {
+	best, bestDist := "", len(name)/5+2
+	for key := range keys {
+		d := levenshtein(name, key)
+		if d < bestDist || d == bestDist && key < best {
+			best, bestDist = key, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
 }
 
 func NewEnvDecoder(tagname string) Decoder {
-	return KVWrapper(&envDecoder{tagname})
+	return NewEnvDecoderOptions(EnvOptions{Tagname: tagname})
+}
+
+// NewEnvDecoderOptions returns an env decoder configured by opts.
+func NewEnvDecoderOptions(opts EnvOptions) Decoder {
+	kv := KVWrapper(&envDecoder{tagname: opts.Tagname, prefix: opts.Prefix})
+	return &envwrapper{kvwrapper: kv.(*kvwrapper), opts: opts}
+}
+
+// EnvVar returns the name of the env var d reads the field at index of dst
+// from; ok is false if d is not an env decoder.
+func EnvVar(d Decoder, dst interface{}, index []int) (name string, ok bool) {
+	if s, ok := d.(*strictDecoder); ok {
+		d = s.d
+	}
+	e, ok := d.(*envwrapper)
+	if !ok {
+		return "", false
+	}
+	return e.Source(dst, index), true
 }
@@ -1,7 +1,10 @@
 package decoders
 
 import (
+	"context"
+	"errors"
 	"os"
+	"reflect"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -87,3 +90,107 @@ func TestEnvDecoderNested(t *testing.T) {
 	Ω(dst.Embedded.N).Should(Equal(1984))
 	Ω(dst.Nested.S).Should(Equal(""))
 }
+
+func TestEnvDecoderError(t *testing.T) {
+	RegisterTestingT(t)
+
+	dst := struct {
+		Nested struct {
+			N int
+		}
+	}{}
+
+	os.Setenv("NESTED_N", "abc")
+	defer os.Unsetenv("NESTED_N")
+
+	err := NewEnvDecoder("").Decode(&dst)
+	Ω(err).Should(MatchError("NESTED_N: cannot parse as int"))
+
+	var ke *KeyError
+	Ω(errors.As(err, &ke)).Should(BeTrue())
+	Ω(ke.Key).Should(Equal("NESTED_N"))
+	Ω(ke.Index).Should(Equal([]int{0, 0}))
+
+	var ve *ValueError
+	Ω(errors.As(err, &ve)).Should(BeTrue())
+	Ω(ve.Value).Should(Equal("abc"))
+	Ω(ve.Type).Should(Equal(reflect.TypeOf(0)))
+}
+
+func TestEnvDecoderPrefix(t *testing.T) {
+	RegisterTestingT(t)
+
+	type B struct {
+		M int
+	}
+	dst := struct {
+		B
+		N      int
+		Nested struct {
+			N int
+		}
+	}{}
+
+	os.Setenv("N", "1")
+	os.Setenv("APP_N", "2")
+	os.Setenv("APP_M", "3")
+	os.Setenv("APP_NESTED_N", "4")
+	defer os.Unsetenv("N")
+	defer os.Unsetenv("APP_N")
+	defer os.Unsetenv("APP_M")
+	defer os.Unsetenv("APP_NESTED_N")
+
+	d := NewEnvDecoderOptions(EnvOptions{Prefix: "APP_"})
+	Ω(d.Decode(&dst)).Should(Succeed())
+	Ω(dst.N).Should(Equal(2))
+	Ω(dst.B.M).Should(Equal(3))
+	Ω(dst.Nested.N).Should(Equal(4))
+	Ω(d.(Sourcer).Source(&dst, []int{2, 0})).Should(Equal("APP_NESTED_N"))
+}
+
+func TestEnvDecoderUnused(t *testing.T) {
+	RegisterTestingT(t)
+
+	dst := struct {
+		DatabaseURL string `env:"DATABASE_URL"`
+		Port        int
+	}{}
+
+	os.Setenv("APP_DATABSE_URL", "postgres://")
+	os.Setenv("APP_PORT", "80")
+	os.Setenv("APP_VERBOSE", "true")
+	defer os.Unsetenv("APP_DATABSE_URL")
+	defer os.Unsetenv("APP_PORT")
+	defer os.Unsetenv("APP_VERBOSE")
+
+	opts := EnvOptions{Tagname: "env", Prefix: "APP_"}
+	Ω(NewEnvDecoderOptions(opts).Decode(&dst)).Should(Succeed())
+	Ω(dst.Port).Should(Equal(80))
+
+	expErr := &UnusedEnvError{Vars: []UnusedEnv{
+		{Name: "APP_DATABSE_URL", Suggestion: "APP_DATABASE_URL"},
+		{Name: "APP_VERBOSE"},
+	}}
+
+	opts.Unused = FailUnused
+	err := NewEnvDecoderOptions(opts).Decode(&dst)
+	Ω(err).Should(Equal(expErr))
+	Ω(err).Should(MatchError("unused env vars: " +
+		"APP_DATABSE_URL (did you mean APP_DATABASE_URL?), APP_VERBOSE"))
+
+	opts.Unused = WarnUnused
+	err = NewEnvDecoderOptions(opts).Decode(&dst)
+	Ω(err).Should(Equal(&Warning{Err: expErr}))
+
+	opts.Ignore = []string{"APP_VERBOSE"}
+	err = NewEnvDecoderOptions(opts).Decode(&dst)
+	Ω(err).Should(MatchError("unused env vars: " +
+		"APP_DATABSE_URL (did you mean APP_DATABASE_URL?)"))
+	ctx := WithIgnoredEnv(context.Background(), "APP_DATABSE_URL")
+	d := Strict(NewEnvDecoderOptions(opts))
+	Ω(DecodeContext(ctx, d, &dst)).Should(Succeed())
+
+	// Without a prefix every env var would be unused
+	opts.Prefix = ""
+	Ω(NewEnvDecoderOptions(opts).Decode(&dst)).Should(Succeed())
+}
@@ -0,0 +1,84 @@
+package decoders
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// ErrSkipped is wrapped by the errors of decoders that had nothing to decode,
+// e.g. an optional file that does not exist. Flow does not report such errors
+// as failures.
+var ErrSkipped = errors.New("skipped")
+
+// KeyError is returned by decoders that fail to decode the value of a single
+// key into the field at Index of the destination.
+type KeyError struct {
+	Key   string
+	Index []int
+	Err   error
+}
+
+func (e *KeyError) Error() string {
+	return e.Key + ": " + e.Err.Error()
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// ValueError is returned when a plain text value, e.g. of an env var, cannot
+// be parsed into a value of Type. The message leaves out the value as it may
+// be a secret.
+type ValueError struct {
+	Value string
+	Type  reflect.Type
+	Err   error
+}
+
+func (e *ValueError) Error() string {
+	return "cannot parse as " + e.Type.String()
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// Warning wraps an error a decoder reports without failing, e.g. unused env
+// vars. Flow reports warnings separately from errors.
+type Warning struct {
+	Err error
+}
+
+func (w *Warning) Error() string {
+	return w.Err.Error()
+}
+
+func (w *Warning) Unwrap() error {
+	return w.Err
+}
+
+// UnusedEnv is an env var that starts with the prefix of an env decoder, but
+// does not match any field.
+type UnusedEnv struct {
+	Name string
+	// Suggestion is the env var name the decoder reads that is the closest
+	// to Name, if any is close enough to suspect a typo.
+	Suggestion string
+}
+
+// UnusedEnvError lists the unused env vars found by an env decoder.
+type UnusedEnvError struct {
+	Vars []UnusedEnv
+}
+
+func (e *UnusedEnvError) Error() string {
+	msgs := make([]string, len(e.Vars))
+	for i, v := range e.Vars {
+		msgs[i] = v.Name
+		if v.Suggestion != "" {
+			msgs[i] += " (did you mean " + v.Suggestion + "?)"
+		}
+	}
+	return "unused env vars: " + strings.Join(msgs, ", ")
+}
@@ -0,0 +1,184 @@
+package decoders
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+type setFieldsKey struct{}
+
+// WithSetFields returns a copy of ctx that makes the decoders running with it
+// call fn with the index of every field of the destination they set, even if
+// they set it to the value it already had. Decoders that cannot tell which
+// fields they set do not call fn.
+func WithSetFields(ctx context.Context,
+	fn func(index []int)) context.Context {
+
+	return context.WithValue(ctx, setFieldsKey{}, fn)
+}
+
+// SetField reports that the field at index of the destination was set, see
+// WithSetFields. It does nothing if ctx was not created by WithSetFields.
+func SetField(ctx context.Context, index []int) {
+	if fn, ok := ctx.Value(setFieldsKey{}).(func(index []int)); ok {
+		fn(index)
+	}
+}
+
+// recordsFields reports whether ctx was created by WithSetFields.
+func recordsFields(ctx context.Context) bool {
+	_, ok := ctx.Value(setFieldsKey{}).(func(index []int))
+	return ok
+}
+
+// KeyChecker is implemented by KVStores that can tell whether they hold a
+// value for a key. The decoders returned by KVWrapper report the fields of
+// such keys as set, see WithSetFields.
+type KeyChecker interface {
+	HasKey(key string) bool
+}
+
+// FieldsUnmarshaller is implemented by unmarshallers that can tell which
+// fields of dst the content bs sets. The file decoders report such fields as
+// set, see WithSetFields.
+type FieldsUnmarshaller interface {
+	Fields(bs []byte, dst interface{}) ([][]int, error)
+}
+
+// docUnmarshaller is implemented by the unmarshallers of the formats that
+// decode into a generic document described by a docFormat.
+type docUnmarshaller interface {
+	unmarshallDoc(bs []byte) (interface{}, error)
+	format() docFormat
+}
+
+// docFields implements FieldsUnmarshaller for a docUnmarshaller.
+func docFields(u docUnmarshaller, bs []byte, dst interface{}) ([][]int, error) {
+	doc, err := u.unmarshallDoc(bs)
+	if err != nil {
+		return nil, err
+	}
+	return u.format().fields(doc, reflect.TypeOf(dst)), nil
+}
+
+// docFormat describes how an unmarshaller maps the keys of a document to
+// struct fields.
+type docFormat struct {
+	tagname string
+	// mapFunc maps the names of the fields without a name in their tag
+	mapFunc func(string) string
+	// fold matches the keys case-insensitively
+	fold bool
+	// inline lists the tag options that inline the fields of a struct into
+	// its parent
+	inline []string
+	// inlineEmbedded inlines the fields of embedded structs without a name
+	// in their tag
+	inlineEmbedded bool
+}
+
+// fields returns the indexes of the fields of the struct type t that doc, a
+// document decoded into an interface{}, sets.
+func (f docFormat) fields(doc interface{}, t reflect.Type) [][]int {
+	r := [][]int{}
+	f.walk(doc, t, []int{}, func(index []int, v interface{}) {
+		r = append(r, index)
+	})
+	return r
+}
+
+// walk calls fn with the index of every field of the struct type t that doc
+// sets, and the value doc sets it to.
+func (f docFormat) walk(doc interface{}, t reflect.Type, prefix []int,
+	fn func(index []int, v interface{})) {
+
+	t = reflectx.Deref(t)
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	var m map[string]interface{}
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		m = d
+	case map[interface{}]interface{}:
+		m = make(map[string]interface{}, len(d))
+		for k, v := range d {
+			m[fmt.Sprint(k)] = v
+		}
+	case []map[string]interface{}:
+		// HCL decodes blocks into lists of objects
+		for _, e := range d {
+			f.walk(e, t, prefix, fn)
+		}
+		return
+	default:
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		opts := strings.Split(sf.Tag.Get(f.tagname), ",")
+		name := opts[0]
+		if name == "-" {
+			continue
+		}
+		index := append(append([]int{}, prefix...), i)
+
+		if f.isInline(sf, opts) {
+			f.walk(doc, sf.Type, index, fn)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+			if f.mapFunc != nil {
+				name = f.mapFunc(name)
+			}
+		}
+		for k, v := range m {
+			if k == name || f.fold && strings.EqualFold(k, name) {
+				fn(index, v)
+				f.walk(v, sf.Type, index, fn)
+				break
+			}
+		}
+	}
+}
+
+func (f docFormat) isInline(sf reflect.StructField, opts []string) bool {
+	if reflectx.Deref(sf.Type).Kind() != reflect.Struct {
+		return false
+	}
+	for _, opt := range opts[1:] {
+		for _, inline := range f.inline {
+			if opt == inline {
+				return true
+			}
+		}
+	}
+	return f.inlineEmbedded && sf.Anonymous && opts[0] == ""
+}
+
+// fields returns the indexes of the fields of dst whose keys the store of d
+// holds. It returns none if the store does not implement KeyChecker.
+func (d kvwrapper) fields(dst interface{}) [][]int {
+	r := [][]int{}
+	kc, ok := d.store.(KeyChecker)
+	if !ok {
+		return r
+	}
+	t := reflectx.Deref(reflect.TypeOf(dst))
+	for key, index := range d.mapper.TypeMap(t) {
+		if kc.HasKey(key) {
+			r = append(r, index)
+		}
+	}
+	return r
+}
@@ -0,0 +1,124 @@
+package decoders
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type fieldsEmbedded struct {
+	Level string
+}
+
+type fieldsConfig struct {
+	fieldsEmbedded `yaml:",inline" hcl:",squash"`
+	Port           int
+	Name           string `yaml:"app_name" json:"app_name" toml:"app_name" hcl:"app_name"`
+	Server         struct {
+		Host string
+		TLS  bool
+	}
+	Skipped string `yaml:"-" json:"-" toml:"-" hcl:"-"`
+}
+
+// decodeFields runs d on dst and returns the indexes of the fields d
+// reported to set, sorted.
+func decodeFields(d Decoder, dst interface{}) [][]int {
+	r := [][]int{}
+	ctx := WithSetFields(context.Background(), func(index []int) {
+		r = append(r, index)
+	})
+	Ω(DecodeContext(ctx, d, dst)).Should(Succeed())
+	sort.Slice(r, func(i, j int) bool {
+		for k := 0; k < len(r[i]) && k < len(r[j]); k++ {
+			if r[i][k] != r[j][k] {
+				return r[i][k] < r[j][k]
+			}
+		}
+		return len(r[i]) < len(r[j])
+	})
+	return r
+}
+
+func TestSetFields(t *testing.T) {
+	RegisterTestingT(t)
+
+	exp := [][]int{{0, 0}, {1}, {2}, {3}, {3, 1}}
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"config.yaml": "level: info\nport: 0\napp_name: x\n" +
+			"server:\n  tls: false\nskipped: x\nunknown: 1\n",
+		"config.json": `{"Level": "info", "port": 0, "app_name": "x", ` +
+			`"server": {"TLS": false}, "skipped": "x", "unknown": 1}`,
+		"config.toml": "level = 'info'\nport = 0\napp_name = 'x'\n" +
+			"skipped = 'x'\nunknown = 1\n[server]\ntls = false\n",
+		"config.hcl": "level = \"info\"\nport = 0\napp_name = \"x\"\n" +
+			"server {\n  tls = false\n}\nskipped = \"x\"\nunknown = 1\n",
+	} {
+		filename := filepath.Join(dir, name)
+		Ω(ioutil.WriteFile(filename, []byte(content), 0644)).Should(Succeed())
+
+		var d Decoder
+		switch filepath.Ext(name) {
+		case ".yaml":
+			d = NewYamlFileDecoder(filename)
+		case ".json":
+			d = NewJsonFileDecoder(filename)
+		case ".toml":
+			d = NewTomlFileDecoder(filename)
+		case ".hcl":
+			d = NewHclFileDecoder(filename)
+		}
+		Ω(decodeFields(d, &fieldsConfig{})).Should(Equal(exp), name)
+	}
+
+	type Cfg struct {
+		Port   int
+		Name   string
+		Server struct {
+			Host string
+			TLS  bool
+		}
+	}
+
+	os.Setenv("PORT", "0")
+	os.Setenv("SERVER_HOST", "")
+	defer os.Unsetenv("PORT")
+	defer os.Unsetenv("SERVER_HOST")
+	Ω(decodeFields(NewEnvDecoder(""), &Cfg{})).Should(Equal([][]int{{0}}))
+
+	Ω(decodeFields(NewStructDecoder(struct {
+		Port   int
+		Server struct{ TLS bool }
+	}{}, "", ""), &Cfg{})).Should(Equal([][]int{{0}, {2, 1}}))
+
+	filename := filepath.Join(dir, "config.ini")
+	Ω(ioutil.WriteFile(filename, []byte("port = 0\n[server]\ntls = false\n"),
+		0644)).Should(Succeed())
+	Ω(decodeFields(NewIniFileDecoder(filename), &Cfg{})).
+		Should(Equal([][]int{{0}, {2, 1}}))
+
+	filename = filepath.Join(dir, "config.properties")
+	Ω(ioutil.WriteFile(filename, []byte("port=0\nserver.tls=false\n"),
+		0644)).Should(Succeed())
+	Ω(decodeFields(NewPropertiesFileDecoder(filename), &Cfg{})).
+		Should(Equal([][]int{{0}, {2, 1}}))
+
+	filename = filepath.Join(dir, ".env")
+	Ω(ioutil.WriteFile(filename, []byte("PORT=0\nNAME=\nSERVER_TLS=false\n"),
+		0644)).Should(Succeed())
+	Ω(decodeFields(NewDotenvFileDecoder(filename), &Cfg{})).
+		Should(Equal([][]int{{0}, {2, 1}}))
+
+	type Defaults struct {
+		Port int `default:"0"`
+		Name string
+	}
+	Ω(decodeFields(NewDefaultsDecoder(), &Defaults{})).
+		Should(Equal([][]int{{0}}))
+}
@@ -0,0 +1,244 @@
+package decoders
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// flagStore is the KVStore of the flags passed on the command line, keyed by
+// flag name.
+type flagStore struct {
+	values map[string]string
+}
+
+func (s flagStore) DecodeKey(key string, dst interface{}) error {
+	if val, ok := s.values[key]; ok {
+		return parseRawValue(val, dst)
+	}
+	return nil
+}
+
+func (s flagStore) HasKey(key string) bool {
+	_, ok := s.values[key]
+	return ok
+}
+
+func (s flagStore) Kind() string {
+	return "flag"
+}
+
+func (s flagStore) Name() string {
+	return "flags"
+}
+
+func (s flagStore) Tagname() string {
+	return "flag"
+}
+
+func (s flagStore) MapFunc() func(string) string {
+	return kebabCase
+}
+
+func (s flagStore) ReduceFunc() func(string, string) string {
+	return reflectx.DelimiterKeyReducer(".")
+}
+
+// flagValue is the flag.Value of a config key, it records the values the
+// flag is set to in the store.
+type flagValue struct {
+	key    string
+	def    string
+	isBool bool
+	store  *flagStore
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.def
+}
+
+func (v *flagValue) Set(s string) error {
+	v.store.values[v.key] = s
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+type flagDecoder struct {
+	*kvwrapper
+	fs         *flag.FlagSet
+	args       []string
+	store      *flagStore
+	registered bool
+	once       sync.Once
+	err        error
+}
+
+func (d *flagDecoder) Decode(dst interface{}) error {
+	return d.DecodeContext(context.Background(), dst)
+}
+
+func (d *flagDecoder) DecodeContext(ctx context.Context,
+	dst interface{}) error {
+
+	d.once.Do(func() {
+		if !d.registered {
+			// Without the defaults only the `default` tags are shown
+			zero := reflect.Zero(reflect.TypeOf(dst)).Interface()
+			d.err = d.register(zero)
+		}
+		if d.err == nil {
+			d.err = d.fs.Parse(d.args)
+		}
+	})
+	if d.err != nil {
+		return d.err
+	}
+	return d.kvwrapper.DecodeContext(ctx, dst)
+}
+
+// register registers a flag in fs for every key of cfg. The keys whose flags
+// fs already defines are left out, the first of them is reported as an error.
+func (d *flagDecoder) register(cfg interface{}) error {
+	d.registered = true
+	if cfg == nil {
+		return nil
+	}
+	t := reflectx.Deref(reflect.TypeOf(cfg))
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	v := reflect.Indirect(reflect.ValueOf(cfg))
+	if !v.IsValid() {
+		v = reflect.Zero(t)
+	}
+	tm := d.mapper.TypeMap(t)
+	fm := d.mapper.FieldMapReadOnly(v)
+
+	keys := make([]string, 0, len(tm))
+	for key := range tm {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var err error
+	for _, key := range keys {
+		if d.fs.Lookup(key) != nil {
+			if err == nil {
+				err = fmt.Errorf("%s flag redefined: %s", d.fs.Name(), key)
+			}
+			continue
+		}
+		sf := t.FieldByIndex(tm[key])
+		d.fs.Var(&flagValue{
+			key:    key,
+			def:    flagDefault(sf, fm[key]),
+			isBool: reflectx.Deref(sf.Type).Kind() == reflect.Bool,
+			store:  d.store,
+		}, key, sf.Tag.Get("desc"))
+	}
+	return err
+}
+
+// flagDefault formats the default value of the field sf, whose value in the
+// defaults is v, for the help text: the value of its `default` tag if it has
+// one, and v otherwise. Zero values are left out, the values of secret fields
+// are replaced with Redacted.
+func flagDefault(sf reflect.StructField, v reflect.Value) string {
+	secret := IsSecret(sf)
+	if tag, ok := sf.Tag.Lookup("default"); ok {
+		if secret && tag != "" {
+			return Redacted
+		}
+		return tag
+	}
+	if !v.IsValid() || v.IsZero() {
+		return ""
+	}
+	if secret {
+		return Redacted
+	}
+	return fmt.Sprint(reflect.Indirect(v).Interface())
+}
+
+// kebabCase converts a Go field name to a flag name, e.g. PoolSize to
+// pool-size and HTTPPort to http-port.
+func kebabCase(name string) string {
+	rs := []rune(name)
+	var b strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				unicode.IsUpper(prev) && nextLower {
+
+				b.WriteByte('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// NewFlagDecoder returns a decoder of the command-line flags args. On the
+// first decode it registers a flag in fs for every key of the config, e.g.
+// --db.pool-size for DB.PoolSize, unless RegisterFlags did already, and parses
+// args with fs. The flag names can be overridden with the `flag` struct tag,
+// the help text is taken from the `desc` tag and the default values shown from
+// the `default` tag, or the defaults given to RegisterFlags.
+//
+// Only the fields of the flags passed in args are set, their values are
+// parsed the same way env var values are, except that strings are taken as
+// is. Boolean flags may be passed without a value, e.g. --verbose.
+//
+// If fs already defines the flag of a key, the key is left out and every
+// decode fails. The parse errors of fs, including flag.ErrHelp, are returned
+// by every decode as well; fs exits on them instead if its error handling is
+// flag.ExitOnError.
+func NewFlagDecoder(fs *flag.FlagSet, args []string) Decoder {
+	store := &flagStore{values: map[string]string{}}
+	return &flagDecoder{
+		kvwrapper: KVWrapper(store).(*kvwrapper),
+		fs:        fs,
+		args:      args,
+		store:     store,
+	}
+}
+
+// RegisterFlags registers the flags of the flag decoder d in its flag set
+// right away, rather than on the first decode, so the flag set can print its
+// help before the config is loaded. The default values shown are taken from
+// cfg, a config struct or a pointer to one, typically the defaults given to
+// the flow.
+//
+// It fails if d is not a flag decoder or its flags are already registered.
+// If the flag set already defines the flag of a key, the error is returned by
+// every decode of d as well.
+func RegisterFlags(d Decoder, cfg interface{}) error {
+	if s, ok := d.(*strictDecoder); ok {
+		d = s.d
+	}
+	fd, ok := d.(*flagDecoder)
+	if !ok {
+		return fmt.Errorf("%s is not a flag decoder", Name(d))
+	}
+	if fd.registered {
+		return fmt.Errorf("the flags of %s are already registered",
+			fd.fs.Name())
+	}
+	fd.err = fd.register(cfg)
+	return fd.err
+}
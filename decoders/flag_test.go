@@ -0,0 +1,193 @@
+package decoders
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestKebabCase(t *testing.T) {
+	RegisterTestingT(t)
+
+	for name, exp := range map[string]string{
+		"Port":     "port",
+		"PoolSize": "pool-size",
+		"HTTPPort": "http-port",
+		"DB":       "db",
+		"Str1":     "str1",
+		"Num2Str":  "num2-str",
+		"camelID":  "camel-id",
+	} {
+		Ω(kebabCase(name)).Should(Equal(exp), name)
+	}
+}
+
+func TestFlagDecoder(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Name    string        `desc:"the name"`
+		Verbose bool          `desc:"log more"`
+		Timeout time.Duration `default:"30s"`
+		Hosts   []string      `flag:"host-list"`
+		DB      struct {
+			PoolSize int `desc:"max connections"`
+			User     string
+		}
+	}
+
+	defaults := Cfg{Name: "def"}
+	defaults.DB.User = "root"
+
+	var out bytes.Buffer
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(&out)
+	d := NewFlagDecoder(fs, []string{
+		"--db.pool-size=10", "-name", "a: b", "--verbose",
+		"--host-list", "[x, y]", "rest",
+	})
+
+	// The flags are registered before the first decode
+	Ω(RegisterFlags(d, &defaults)).Should(Succeed())
+	Ω(RegisterFlags(d, &defaults)).Should(MatchError(
+		"the flags of app are already registered"))
+	help := "" +
+		"  -db.pool-size value\n" +
+		"    \tmax connections\n" +
+		"  -db.user value\n" +
+		"    \t (default root)\n" +
+		"  -host-list value\n" +
+		"    \t\n" +
+		"  -name value\n" +
+		"    \tthe name (default def)\n" +
+		"  -timeout value\n" +
+		"    \t (default 30s)\n" +
+		"  -verbose\n" +
+		"    \tlog more\n"
+	fs.PrintDefaults()
+	Ω(out.String()).Should(Equal(help))
+
+	v := defaults
+	v.Name = "set by another decoder"
+	Ω(d.Decode(&v)).Should(Succeed())
+	Ω(v.Name).Should(Equal("a: b"))
+	Ω(v.Verbose).Should(BeTrue())
+	Ω(v.Timeout).Should(BeZero())
+	Ω(v.Hosts).Should(Equal([]string{"x", "y"}))
+	Ω(v.DB.PoolSize).Should(Equal(10))
+	Ω(v.DB.User).Should(Equal("root"))
+	Ω(fs.Args()).Should(Equal([]string{"rest"}))
+	Ω(Name(d)).Should(Equal("flags"))
+	Ω(d.(Sourcer).Source(&v, []int{4, 0})).Should(Equal("db.pool-size"))
+
+	// The flags are parsed once, later decodes only set the passed fields
+	v = Cfg{}
+	Ω(d.Decode(&v)).Should(Succeed())
+	Ω(v.Name).Should(Equal("a: b"))
+	Ω(v.DB.User).Should(BeEmpty())
+
+	// The defaults shown are the ones given to RegisterFlags
+	out.Reset()
+	fs.PrintDefaults()
+	Ω(out.String()).Should(Equal(help))
+
+	// Without RegisterFlags only the `default` tags are shown
+	out.Reset()
+	fs = flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(&out)
+	d = NewFlagDecoder(fs, nil)
+	Ω(d.Decode(&v)).Should(Succeed())
+	fs.PrintDefaults()
+	Ω(out.String()).Should(ContainSubstring("  -db.user value\n    \t\n"))
+	Ω(out.String()).Should(ContainSubstring("\t (default 30s)\n"))
+
+	Ω(RegisterFlags(NewEnvDecoder(""), &v)).Should(MatchError(
+		"env is not a flag decoder"))
+}
+
+type testSecret string
+
+func (s testSecret) String() string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+func TestFlagDecoderSecrets(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Password string     `config:",secret" default:"hunter2"`
+		Token    testSecret `default:"tok"`
+		Key      string     `config:",secret"`
+		Empty    string     `config:",secret"`
+	}
+
+	var out bytes.Buffer
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(&out)
+	d := NewFlagDecoder(fs, nil)
+	Ω(RegisterFlags(d, Cfg{Key: "k"})).Should(Succeed())
+	fs.PrintDefaults()
+	Ω(out.String()).Should(Equal("" +
+		"  -empty value\n" +
+		"    \t\n" +
+		"  -key value\n" +
+		"    \t (default [REDACTED])\n" +
+		"  -password value\n" +
+		"    \t (default [REDACTED])\n" +
+		"  -token value\n" +
+		"    \t (default [REDACTED])\n"))
+}
+
+func TestFlagDecoderErrors(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Port int
+	}
+
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	d := NewFlagDecoder(fs, []string{"--port", "abc"})
+	var v Cfg
+	Ω(d.Decode(&v)).Should(MatchError("port: cannot parse as int"))
+
+	fs = flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	d = NewFlagDecoder(fs, []string{"--unknown"})
+	Ω(d.Decode(&v)).Should(MatchError(
+		"flag provided but not defined: -unknown"))
+	Ω(d.Decode(&v)).Should(MatchError(
+		"flag provided but not defined: -unknown"))
+
+	fs = flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(&bytes.Buffer{})
+	d = NewFlagDecoder(fs, []string{"-h"})
+	Ω(d.Decode(&v)).Should(MatchError(flag.ErrHelp))
+
+	// Flags already defined in fs are reported instead of panicking
+	for _, register := range []bool{true, false} {
+		fs = flag.NewFlagSet("app", flag.ContinueOnError)
+		fs.SetOutput(&bytes.Buffer{})
+		fs.String("port", "", "")
+		d = NewFlagDecoder(fs, []string{"--port", "80"})
+		if register {
+			Ω(RegisterFlags(d, &v)).Should(MatchError(
+				"app flag redefined: port"))
+		}
+		Ω(d.Decode(&v)).Should(MatchError("app flag redefined: port"))
+		Ω(v.Port).Should(BeZero())
+	}
+
+	// A nil pointer registers the flags of its type
+	fs = flag.NewFlagSet("app", flag.ContinueOnError)
+	d = NewFlagDecoder(fs, []string{"--port", "80"})
+	Ω(RegisterFlags(d, (*Cfg)(nil))).Should(Succeed())
+	Ω(d.Decode(&v)).Should(Succeed())
+	Ω(v.Port).Should(Equal(80))
+}
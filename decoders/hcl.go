@@ -0,0 +1,337 @@
+package decoders
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PlanitarInc/go-config/reflectx"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/parser"
+)
+
+type hclUnmarshaller struct{}
+
+func (u hclUnmarshaller) Unmarshall(bs []byte, dst interface{}) error {
+	f, err := hcl.ParseBytes(bs)
+	if err != nil {
+		return err
+	}
+	list, ok := f.Node.(*ast.ObjectList)
+	if !ok {
+		return hcl.DecodeObject(dst, f)
+	}
+
+	t := reflect.TypeOf(dst)
+	expandBlocks(list, t)
+	if err := hcl.DecodeObject(dst, list); err != nil {
+		var pe *parser.PosError
+		if errors.As(err, &pe) {
+			return err
+		}
+		// The decoder does not report the positions of invalid values, look
+		// for the value that fails on its own
+		if perr := locateFieldError("", list, t); perr != nil {
+			return perr
+		}
+		return err
+	}
+	return nil
+}
+
+var hclFormat = docFormat{
+	tagname: "hcl",
+	fold:    true,
+	inline:  []string{"squash"},
+}
+
+func (u hclUnmarshaller) Fields(bs []byte, dst interface{}) ([][]int, error) {
+	return docFields(u, bs, dst)
+}
+
+func (u hclUnmarshaller) unmarshallDoc(bs []byte) (interface{}, error) {
+	var doc interface{}
+	err := hcl.Unmarshal(bs, &doc)
+	return doc, err
+}
+
+func (u hclUnmarshaller) format() docFormat {
+	return hclFormat
+}
+
+func (u hclUnmarshaller) Name() string {
+	return "hcl"
+}
+
+// UnmarshallStrict rejects keys that do not match any field and keys set more
+// than once, unless they are blocks setting a slice. The keys of labeled
+// blocks, e.g. service "web" {...}, are only checked up to the labels.
+func (u hclUnmarshaller) UnmarshallStrict(bs []byte, dst interface{}) error {
+	f, err := hcl.ParseBytes(bs)
+	if err != nil {
+		return err
+	}
+	if list, ok := f.Node.(*ast.ObjectList); ok {
+		unknown := []string{}
+		err := checkHclKeys("", list, reflect.TypeOf(dst), &unknown)
+		if err != nil {
+			return err
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("unknown fields: %s", strings.Join(unknown, ", "))
+		}
+	}
+	return u.Unmarshall(bs, dst)
+}
+
+// checkHclKeys adds the dotted keys of the items of list that do not match a
+// field of the struct type t to unknown, and fails on the first key set more
+// than once.
+func checkHclKeys(ns string, list *ast.ObjectList, t reflect.Type,
+	unknown *[]string) error {
+
+	t = reflectx.Deref(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := map[string]reflect.StructField{}
+	hclStructFields(t, fields)
+
+	seen := map[string]bool{}
+	for _, item := range list.Items {
+		name := item.Keys[0].Token.Value().(string)
+		key := name
+		if ns != "" {
+			key = ns + "." + name
+		}
+		sf, ok := fields[strings.ToLower(name)]
+		if !ok {
+			*unknown = append(*unknown, key)
+			continue
+		}
+		if len(item.Keys) > 1 {
+			continue
+		}
+
+		ft := reflectx.Deref(sf.Type)
+		if ft.Kind() != reflect.Slice {
+			if seen[strings.ToLower(name)] {
+				return &parser.PosError{
+					Pos: item.Pos(),
+					Err: fmt.Errorf("key %s already set", key),
+				}
+			}
+			seen[strings.ToLower(name)] = true
+		} else {
+			ft = ft.Elem()
+		}
+
+		switch v := item.Val.(type) {
+		case *ast.ObjectType:
+			if err := checkHclKeys(key, v.List, ft, unknown); err != nil {
+				return err
+			}
+		case *ast.ListType:
+			for _, n := range v.List {
+				ot, ok := n.(*ast.ObjectType)
+				if !ok {
+					continue
+				}
+				if err := checkHclKeys(key, ot.List, ft, unknown); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hclStructFields adds the fields of the struct type t to fields by their
+// lowercase HCL names, including the fields of the squashed structs.
+func hclStructFields(t reflect.Type, fields map[string]reflect.StructField) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		opts := strings.Split(sf.Tag.Get("hcl"), ",")
+		if hclFormat.isInline(sf, opts) {
+			hclStructFields(reflectx.Deref(sf.Type), fields)
+			continue
+		}
+		name := sf.Name
+		if opts[0] != "" {
+			name = opts[0]
+		}
+		fields[strings.ToLower(name)] = sf
+	}
+}
+
+// hclFields calls fn for every item of list that sets a field of the struct
+// type t, with the field and the dotted key of the item; it stops once fn
+// returns false. Labeled blocks, e.g. service "web" {...}, are left out.
+func hclFields(ns string, list *ast.ObjectList, t reflect.Type,
+	fn func(key string, item *ast.ObjectItem, sf reflect.StructField) bool) {
+
+	t = reflectx.Deref(t)
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := sf.Name
+		if tag := strings.Split(sf.Tag.Get("hcl"), ",")[0]; tag != "" {
+			name = tag
+		}
+		for _, item := range list.Items {
+			if len(item.Keys) != 1 {
+				continue
+			}
+			key := item.Keys[0].Token.Value().(string)
+			if key != name && !strings.EqualFold(key, name) {
+				continue
+			}
+			if ns != "" {
+				key = ns + "." + key
+			}
+			if !fn(key, item, sf) {
+				return
+			}
+		}
+	}
+}
+
+// expandBlocks rewrites the blocks of list that set slices of structs into
+// lists of objects, e.g. two {...} two {...} into two = [{...}, {...}]. The
+// HCL decoder decodes every attribute of such blocks into a separate element
+// of the slice otherwise.
+func expandBlocks(list *ast.ObjectList, t reflect.Type) {
+	// The first block of every slice is replaced with the list of all its
+	// blocks, the other blocks are dropped
+	lists := map[string]*ast.ObjectItem{}
+	replace := map[*ast.ObjectItem]*ast.ObjectItem{}
+	drop := map[*ast.ObjectItem]bool{}
+
+	hclFields("", list, t, func(_ string, item *ast.ObjectItem,
+		sf reflect.StructField) bool {
+
+		ft := reflectx.Deref(sf.Type)
+		switch v := item.Val.(type) {
+		case *ast.ObjectType:
+			if ft.Kind() != reflect.Slice {
+				expandBlocks(v.List, ft)
+				return true
+			}
+			et := reflectx.Deref(ft.Elem())
+			expandBlocks(v.List, et)
+			if et.Kind() != reflect.Struct {
+				return true
+			}
+			if l, ok := lists[sf.Name]; ok {
+				lt := l.Val.(*ast.ListType)
+				lt.List = append(lt.List, v)
+				lt.Rbrack = v.Rbrace
+				drop[item] = true
+				return true
+			}
+			l := &ast.ObjectItem{
+				Keys:   item.Keys,
+				Assign: item.Assign,
+				Val: &ast.ListType{
+					Lbrack: v.Lbrace,
+					Rbrack: v.Rbrace,
+					List:   []ast.Node{v},
+				},
+			}
+			lists[sf.Name] = l
+			replace[item] = l
+		case *ast.ListType:
+			if ft.Kind() != reflect.Slice {
+				return true
+			}
+			for _, n := range v.List {
+				if ot, ok := n.(*ast.ObjectType); ok {
+					expandBlocks(ot.List, ft.Elem())
+				}
+			}
+		}
+		return true
+	})
+
+	items := make([]*ast.ObjectItem, 0, len(list.Items))
+	for _, item := range list.Items {
+		if l, ok := replace[item]; ok {
+			item = l
+		}
+		if !drop[item] {
+			items = append(items, item)
+		}
+	}
+	list.Items = items
+}
+
+// locateError looks for the value of node that cannot be decoded into a
+// value of type t, and returns the error of decoding it with its position.
+func locateError(key string, node ast.Node, t reflect.Type) error {
+	switch n := node.(type) {
+	case *ast.ObjectType:
+		if reflectx.Deref(t).Kind() == reflect.Struct {
+			return locateFieldError(key, n.List, t)
+		}
+	case *ast.ListType:
+		if st := reflectx.Deref(t); st.Kind() == reflect.Slice {
+			for i, elem := range n.List {
+				k := key + "[" + strconv.Itoa(i) + "]"
+				if err := locateError(k, elem, st.Elem()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	err := hcl.DecodeObject(reflect.New(t).Interface(), node)
+	var pe *parser.PosError
+	if err == nil || errors.As(err, &pe) {
+		return err
+	}
+	return &parser.PosError{
+		Pos: node.Pos(),
+		Err: fmt.Errorf("%s: %w", key, err),
+	}
+}
+
+// locateFieldError is like locateError for the items of list setting the
+// fields of the struct type t.
+func locateFieldError(ns string, list *ast.ObjectList, t reflect.Type) error {
+	var err error
+	hclFields(ns, list, t, func(key string, item *ast.ObjectItem,
+		sf reflect.StructField) bool {
+
+		err = locateError(key, item.Val, sf.Type)
+		return err == nil
+	})
+	return err
+}
+
+// NewHclFileDecoder returns a decoder of the required HCL file. Blocks map to
+// nested struct fields, repeated blocks to slices of structs. Field names are
+// matched case-insensitively and can be overridden with the `hcl` struct tag.
+// Errors include the positions in the file, e.g. "At 3:10: ...".
+func NewHclFileDecoder(filename string) Decoder {
+	return NewFileUnmarshaller(filename, &hclUnmarshaller{})
+}
+
+// NewOptionalHclFileDecoder returns a decoder of the optional HCL file: if the
+// file does not exist, decoding returns an error wrapping ErrSkipped.
+func NewOptionalHclFileDecoder(filename string) Decoder {
+	return NewOptionalFileUnmarshaller(filename, &hclUnmarshaller{})
+}
@@ -0,0 +1,167 @@
+package decoders
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
+)
+
+func TestNewHclFileDecoder(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		var v testHclConfig
+		d := NewHclFileDecoder("./config_test.hcl")
+		Ω(d.Decode(&v)).Should(BeNil())
+
+		expV := testHclConfig{
+			Simple: testYamlConfig_Simple{
+				Bool: true,
+				Num:  1,
+				Str:  "test text",
+				Arr:  []string{"a", "b", "d"},
+			},
+			Nested: testYamlConfig_Nested{
+				One: testYamlConfig_Nested_One{
+					Two: []testYamlConfig_Nested_One_Two{
+						{Three: 3, Common: "c-3"},
+						{Four: 4, Common: "c-4"},
+					},
+					Five: []int{6, 7, 8},
+				},
+				Done: "we are done",
+			},
+			Names: testHclConfig_Names{
+				Lowercase:   "just a lowercase key",
+				Capitalized: "a Capitalized key",
+				CamelCase:   "a camelCase key",
+				PascalCase:  "PascalCase is cool",
+				SnakeCase:   "try snake_case",
+				KebabCase:   "kebab-case is always a pleasure to look at",
+			},
+		}
+
+		Ω(cmp.Diff(v, expV)).Should(BeEmpty())
+		Ω(v).Should(Equal(expV))
+	})
+
+	t.Run("blocks", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		filename := writeHcl(t, `
+server {
+  port = 80
+  tls {
+    cert = "a.pem"
+  }
+}
+
+backend {
+  host = "a"
+  port = 1
+}
+backends = [{ host = "b" }]
+`)
+		type Backend struct {
+			Host string
+			Port int
+		}
+		var v struct {
+			Server struct {
+				Port int
+				TLS  *struct {
+					Cert string
+				}
+			}
+			Backend  []Backend
+			Backends []*Backend
+		}
+		Ω(NewHclFileDecoder(filename).Decode(&v)).Should(Succeed())
+		Ω(v.Server.Port).Should(Equal(80))
+		Ω(v.Server.TLS.Cert).Should(Equal("a.pem"))
+		Ω(v.Backend).Should(Equal([]Backend{{Host: "a", Port: 1}}))
+		Ω(v.Backends).Should(Equal([]*Backend{{Host: "b"}}))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		var v testHclConfig
+		filename := writeHcl(t, "simple {\n  num = \n}\n")
+		Ω(NewHclFileDecoder(filename).Decode(&v)).Should(MatchError(
+			filename + ": At 4:1: object expected closing RBRACE got: EOF"))
+
+		filename = writeHcl(t, "simple {\n  num = \"abc\"\n}\n")
+		Ω(NewHclFileDecoder(filename).Decode(&v)).Should(MatchError(
+			filename + ": At 2:9: simple.num: " +
+				`strconv.ParseInt: parsing "abc": invalid syntax`))
+
+		filename = writeHcl(t, `nested {
+  one {
+    two {
+      three = 3
+    }
+    two {
+      four = "4x"
+    }
+  }
+}
+`)
+		Ω(NewHclFileDecoder(filename).Decode(&v)).Should(MatchError(
+			filename + ": At 7:14: nested.one.two[1].four: " +
+				`strconv.ParseInt: parsing "4x": invalid syntax`))
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		var v testHclConfig
+		d := Strict(NewHclFileDecoder("./config_test.hcl"))
+		Ω(d.Decode(&v)).Should(Succeed())
+
+		filename := writeHcl(t, `simple {
+  num = 1
+  extra = 2
+}
+nested {
+  one {
+    two { three = 3 }
+    two { four = 4, five = 5 }
+  }
+}
+unknown "label" {}
+`)
+		Ω(Strict(NewHclFileDecoder(filename)).Decode(&v)).Should(MatchError(
+			filename + ": unknown fields: " +
+				"nested.one.two.five, simple.extra, unknown"))
+
+		filename = writeHcl(t, "simple {\n  num = 1\n  NUM = 2\n}\n")
+		Ω(NewHclFileDecoder(filename).Decode(&v)).Should(Succeed())
+		Ω(Strict(NewHclFileDecoder(filename)).Decode(&v)).Should(MatchError(
+			filename + ": At 3:3: key simple.NUM already set"))
+	})
+}
+
+func writeHcl(t *testing.T, s string) string {
+	filename := filepath.Join(t.TempDir(), "config.hcl")
+	Ω(ioutil.WriteFile(filename, []byte(s), 0644)).Should(Succeed())
+	return filename
+}
+
+type testHclConfig struct {
+	Simple testYamlConfig_Simple
+	Nested testYamlConfig_Nested
+	Names  testHclConfig_Names
+}
+
+type testHclConfig_Names struct {
+	Lowercase   string
+	Capitalized string
+	CamelCase   string
+	PascalCase  string
+	SnakeCase   string `hcl:"snake_case"`
+	KebabCase   string `hcl:"kebab-case"`
+}
@@ -0,0 +1,158 @@
+package decoders
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// iniStore is the KVStore of the keys of a parsed INI file. The keys are
+// lowercase, the keys of a section are prefixed with the section name and a
+// dot, e.g. "server.port".
+type iniStore struct {
+	values map[string]string
+}
+
+func (s iniStore) DecodeKey(key string, dst interface{}) error {
+	if val, ok := s.values[strings.ToLower(key)]; ok {
+		return parseValue(val, dst)
+	}
+	return nil
+}
+
+func (s iniStore) HasKey(key string) bool {
+	_, ok := s.values[strings.ToLower(key)]
+	return ok
+}
+
+func (s iniStore) Tagname() string {
+	return "ini"
+}
+
+func (s iniStore) MapFunc() func(string) string {
+	return strings.ToLower
+}
+
+func (s iniStore) ReduceFunc() func(string, string) string {
+	return reflectx.DelimiterKeyReducer(".")
+}
+
+type iniUnmarshaller struct{}
+
+func (u iniUnmarshaller) Unmarshall(bs []byte, dst interface{}) error {
+	values, err := parseIni(bs, false)
+	if err != nil {
+		return err
+	}
+	return KVWrapper(&iniStore{values: values}).Decode(dst)
+}
+
+func (u iniUnmarshaller) Fields(bs []byte, dst interface{}) ([][]int, error) {
+	values, err := parseIni(bs, false)
+	if err != nil {
+		return nil, err
+	}
+	return KVWrapper(&iniStore{values: values}).(*kvwrapper).fields(dst), nil
+}
+
+func (u iniUnmarshaller) Name() string {
+	return "ini"
+}
+
+// UnmarshallStrict rejects duplicate keys and keys that do not match any
+// field.
+func (u iniUnmarshaller) UnmarshallStrict(bs []byte, dst interface{}) error {
+	values, err := parseIni(bs, true)
+	if err != nil {
+		return err
+	}
+
+	d := KVWrapper(&iniStore{values: values}).(*kvwrapper)
+	if err := checkUnknownKeys(d, dst, values); err != nil {
+		return err
+	}
+	return d.Decode(dst)
+}
+
+// checkUnknownKeys fails if any of the lowercase keys of values does not
+// match a field of dst mapped by d.
+func checkUnknownKeys(d *kvwrapper, dst interface{},
+	values map[string]string) error {
+
+	known := map[string]bool{}
+	for key := range d.mapper.TypeMap(reflectx.Deref(reflect.TypeOf(dst))) {
+		known[strings.ToLower(key)] = true
+	}
+	unknown := []string{}
+	for key := range values {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown fields: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// parseIni parses the content of an INI file into a map of lowercase keys,
+// see iniStore. Lines starting with ';' or '#' are comments. If a key is set
+// more than once, the last value wins, unless failOnDup is set.
+func parseIni(bs []byte, failOnDup bool) (map[string]string, error) {
+	values := map[string]string{}
+	section := ""
+
+	sc := bufio.NewScanner(bytes.NewReader(bs))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return nil, fmt.Errorf("line %d: unterminated section header", n)
+			}
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			if section == "" {
+				return nil, fmt.Errorf("line %d: empty section name", n)
+			}
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		if len(kv) != 2 || key == "" {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		if section != "" {
+			key = section + "." + key
+		}
+		if _, ok := values[key]; ok && failOnDup {
+			return nil, fmt.Errorf("line %d: key %s already set", n, key)
+		}
+		values[key] = strings.TrimSpace(kv[1])
+	}
+	return values, sc.Err()
+}
+
+// NewIniFileDecoder returns a decoder of the required INI file. Sections map
+// to nested struct fields, e.g. the key port of the section [server] to
+// Server.Port; nested sections are separated with dots, e.g. [server.tls].
+// Section and key names are case-insensitive and can be overridden with the
+// `ini` struct tag. The values are parsed the same way env var values are.
+func NewIniFileDecoder(filename string) Decoder {
+	return NewFileUnmarshaller(filename, &iniUnmarshaller{})
+}
+
+// NewOptionalIniFileDecoder returns a decoder of the optional INI file: if the
+// file does not exist, decoding returns an error wrapping ErrSkipped.
+func NewOptionalIniFileDecoder(filename string) Decoder {
+	return NewOptionalFileUnmarshaller(filename, &iniUnmarshaller{})
+}
@@ -0,0 +1,127 @@
+package decoders
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
+)
+
+func TestNewIniFileDecoder(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		var v testIniConfig
+		d := NewIniFileDecoder("./config_test.ini")
+		Ω(d.Decode(&v)).Should(BeNil())
+
+		expV := testIniConfig{
+			Simple: testYamlConfig_Simple{
+				Bool: true,
+				Num:  1,
+				Str:  "test text",
+				Arr:  []string{"a", "b", "d"},
+			},
+			Nested: testYamlConfig_Nested{
+				One: testYamlConfig_Nested_One{
+					Five: []int{6, 7, 8},
+				},
+				Done: "we are done",
+			},
+			Names: testIniConfig_Names{
+				Lowercase:   "just a lowercase key",
+				Capitalized: "a Capitalized key",
+				CamelCase:   "a camelCase key",
+				PascalCase:  "PascalCase is cool",
+				SnakeCase:   "try snake_case",
+				KebabCase:   "kebab-case is always a pleasure to look at",
+			},
+		}
+
+		Ω(cmp.Diff(v, expV)).Should(BeEmpty())
+		Ω(v).Should(Equal(expV))
+	})
+
+	t.Run("top-level keys", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		filename := writeIni(t, "name = app\n[server]\nport = 80\n")
+		var v struct {
+			Name   string
+			Server struct {
+				Port int
+			}
+		}
+		Ω(NewIniFileDecoder(filename).Decode(&v)).Should(Succeed())
+		Ω(v.Name).Should(Equal("app"))
+		Ω(v.Server.Port).Should(Equal(80))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		var v struct {
+			Server struct {
+				Port int
+			}
+		}
+		filename := writeIni(t, "[server]\nport = abc\n")
+		err := NewIniFileDecoder(filename).Decode(&v)
+		Ω(err).Should(MatchError(filename + ": server.port: " +
+			"cannot parse as int"))
+		var ke *KeyError
+		Ω(errors.As(err, &ke)).Should(BeTrue())
+		Ω(ke.Index).Should(Equal([]int{0, 0}))
+
+		filename = writeIni(t, "[server\nport = 80\n")
+		Ω(NewIniFileDecoder(filename).Decode(&v)).Should(MatchError(
+			filename + ": line 1: unterminated section header"))
+
+		filename = writeIni(t, "[server]\nport\n")
+		Ω(NewIniFileDecoder(filename).Decode(&v)).Should(MatchError(
+			filename + ": line 2: expected key = value"))
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		var v struct {
+			Server struct {
+				Port int
+			}
+		}
+		filename := writeIni(t, "[server]\nport = 80\nport = 81\n")
+		Ω(NewIniFileDecoder(filename).Decode(&v)).Should(Succeed())
+		Ω(v.Server.Port).Should(Equal(81))
+		Ω(Strict(NewIniFileDecoder(filename)).Decode(&v)).Should(MatchError(
+			filename + ": line 3: key server.port already set"))
+
+		filename = writeIni(t, "[server]\nport = 80\nhost = x\n[tls]\n")
+		Ω(Strict(NewIniFileDecoder(filename)).Decode(&v)).Should(MatchError(
+			filename + ": unknown fields: server.host"))
+	})
+}
+
+func writeIni(t *testing.T, s string) string {
+	filename := filepath.Join(t.TempDir(), "config.ini")
+	Ω(ioutil.WriteFile(filename, []byte(s), 0644)).Should(Succeed())
+	return filename
+}
+
+type testIniConfig struct {
+	Simple testYamlConfig_Simple
+	Nested testYamlConfig_Nested
+	Names  testIniConfig_Names
+}
+
+type testIniConfig_Names struct {
+	Lowercase   string
+	Capitalized string
+	CamelCase   string
+	PascalCase  string
+	SnakeCase   string `ini:"snake_case"`
+	KebabCase   string `ini:"kebab-case"`
+}
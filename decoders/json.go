@@ -1,6 +1,10 @@
 package decoders
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
 
 type jsonUnmarshaller struct{}
 
@@ -8,6 +12,104 @@ func (u jsonUnmarshaller) Unmarshall(bs []byte, dst interface{}) error {
 	return json.Unmarshal(bs, dst)
 }
 
+var jsonFormat = docFormat{
+	tagname:        "json",
+	fold:           true,
+	inlineEmbedded: true,
+}
+
+func (u jsonUnmarshaller) Fields(bs []byte, dst interface{}) ([][]int, error) {
+	return docFields(u, bs, dst)
+}
+
+// unmarshallDoc keeps the numbers as they are written, e.g. for
+// redactSecrets to find them in the errors.
+func (u jsonUnmarshaller) unmarshallDoc(bs []byte) (interface{}, error) {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	err := dec.Decode(&doc)
+	return doc, err
+}
+
+func (u jsonUnmarshaller) format() docFormat {
+	return jsonFormat
+}
+
+func (u jsonUnmarshaller) Name() string {
+	return "json"
+}
+
+func (u jsonUnmarshaller) UnmarshallStrict(bs []byte, dst interface{}) error {
+	if err := checkJsonDuplicates(bs); err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(bs))
+	d.DisallowUnknownFields()
+	return d.Decode(dst)
+}
+
+// NewJsonFileDecoder returns a decoder of the required JSON file.
 func NewJsonFileDecoder(filename string) Decoder {
 	return NewFileUnmarshaller(filename, &jsonUnmarshaller{})
 }
+
+// NewOptionalJsonFileDecoder returns a decoder of the optional JSON file: if
+// the file does not exist, decoding returns an error wrapping ErrSkipped.
+func NewOptionalJsonFileDecoder(filename string) Decoder {
+	return NewOptionalFileUnmarshaller(filename, &jsonUnmarshaller{})
+}
+
+// checkJsonDuplicates returns an error for the first key that appears twice
+// in the same object.
+func checkJsonDuplicates(bs []byte) error {
+	d := json.NewDecoder(bytes.NewReader(bs))
+	d.UseNumber()
+	return checkJsonValue(d, bs, "")
+}
+
+func checkJsonValue(d *json.Decoder, bs []byte, path string) error {
+	tok, err := d.Token()
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		seen := map[string]bool{}
+		for d.More() {
+			tok, err := d.Token()
+			if err != nil {
+				return err
+			}
+			key := tok.(string)
+			p := key
+			if path != "" {
+				p = path + "." + key
+			}
+			if seen[key] {
+				return fmt.Errorf("line %d: duplicate key %q",
+					lineAt(bs, d.InputOffset()), p)
+			}
+			seen[key] = true
+			if err := checkJsonValue(d, bs, p); err != nil {
+				return err
+			}
+		}
+		_, err = d.Token()
+	case json.Delim('['):
+		for i := 0; d.More(); i++ {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			if err := checkJsonValue(d, bs, p); err != nil {
+				return err
+			}
+		}
+		_, err = d.Token()
+	}
+	return err
+}
+
+// lineAt returns the 1-based number of the line at offset of bs.
+func lineAt(bs []byte, offset int64) int {
+	return bytes.Count(bs[:offset], []byte("\n")) + 1
+}
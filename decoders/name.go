@@ -0,0 +1,34 @@
+package decoders
+
+import "fmt"
+
+// Namer is implemented by decoders that can describe the source they read,
+// e.g. "yaml file /etc/app.yaml" or "env prefix APP_". Unmarshallers and
+// KVStores may implement it as well to name their format, e.g. "yaml".
+type Namer interface {
+	Name() string
+}
+
+// Name returns the name of d if it implements Namer, and its type otherwise.
+func Name(d Decoder) string {
+	if n, ok := d.(Namer); ok {
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", d)
+}
+
+// Kinder is implemented by decoders that can name the kind of source they
+// read, e.g. "env" or "yaml", shorter than their name. KVStores may implement
+// it as well.
+type Kinder interface {
+	Kind() string
+}
+
+// Kind returns the kind of d if it implements Kinder, and its name otherwise,
+// see Name.
+func Kind(d Decoder) string {
+	if k, ok := d.(Kinder); ok {
+		return k.Kind()
+	}
+	return Name(d)
+}
@@ -0,0 +1,46 @@
+package decoders
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type unnamedDecoder struct{}
+
+func (d unnamedDecoder) Decode(dst interface{}) error {
+	return nil
+}
+
+func TestName(t *testing.T) {
+	RegisterTestingT(t)
+
+	Ω(Name(NewYamlFileDecoder("/etc/app.yaml"))).
+		Should(Equal("yaml file /etc/app.yaml"))
+	Ω(Name(NewOptionalJsonFileDecoder("app.json"))).
+		Should(Equal("optional json file app.json"))
+	Ω(Name(NewEnvDecoder(""))).Should(Equal("env"))
+	Ω(Name(NewEnvDecoderOptions(EnvOptions{Prefix: "APP_"}))).
+		Should(Equal("env prefix APP_"))
+	Ω(Name(NewStructDecoder(struct{}{}, "", ""))).Should(Equal("struct"))
+	Ω(Name(NewDefaultsDecoder())).Should(Equal("defaults"))
+	Ω(Name(Strict(NewYamlFileDecoder("app.yaml")))).
+		Should(Equal("strict yaml file app.yaml"))
+	Ω(Name(unnamedDecoder{})).Should(Equal("decoders.unnamedDecoder"))
+	Ω(Name(NewFileUnmarshaller("app.cfg", nil))).
+		Should(Equal("file app.cfg"))
+}
+
+func TestKind(t *testing.T) {
+	RegisterTestingT(t)
+
+	Ω(Kind(NewYamlFileDecoder("/etc/app.yaml"))).Should(Equal("yaml"))
+	Ω(Kind(NewOptionalIniFileDecoder("app.ini"))).Should(Equal("ini"))
+	Ω(Kind(NewEnvDecoderOptions(EnvOptions{Prefix: "APP_"}))).
+		Should(Equal("env"))
+	Ω(Kind(Strict(NewEnvDecoder("")))).Should(Equal("env"))
+	Ω(Kind(NewStructDecoder(struct{}{}, "", ""))).Should(Equal("struct"))
+	Ω(Kind(NewDefaultsDecoder())).Should(Equal("defaults"))
+	Ω(Kind(unnamedDecoder{})).Should(Equal("decoders.unnamedDecoder"))
+	Ω(Kind(NewFileUnmarshaller("app.cfg", nil))).Should(Equal("file"))
+}
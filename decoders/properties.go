@@ -0,0 +1,224 @@
+package decoders
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// propertiesStore is the KVStore of the keys of a parsed .properties file.
+// The keys are lowercase, e.g. "db.pool.size". The values are literal text:
+// string fields get them as is, other fields parse them like YAML values.
+type propertiesStore struct {
+	values map[string]string
+}
+
+func (s propertiesStore) DecodeKey(key string, dst interface{}) error {
+	if val, ok := s.values[strings.ToLower(key)]; ok {
+		return parseRawValue(val, dst)
+	}
+	return nil
+}
+
+func (s propertiesStore) HasKey(key string) bool {
+	_, ok := s.values[strings.ToLower(key)]
+	return ok
+}
+
+func (s propertiesStore) Tagname() string {
+	return "properties"
+}
+
+func (s propertiesStore) MapFunc() func(string) string {
+	return strings.ToLower
+}
+
+func (s propertiesStore) ReduceFunc() func(string, string) string {
+	return reflectx.DelimiterKeyReducer(".")
+}
+
+type propertiesUnmarshaller struct{}
+
+func (u propertiesUnmarshaller) Unmarshall(bs []byte, dst interface{}) error {
+	values, err := parseProperties(string(bs), false)
+	if err != nil {
+		return err
+	}
+	return KVWrapper(&propertiesStore{values: values}).Decode(dst)
+}
+
+func (u propertiesUnmarshaller) Fields(bs []byte,
+	dst interface{}) ([][]int, error) {
+
+	values, err := parseProperties(string(bs), false)
+	if err != nil {
+		return nil, err
+	}
+	d := KVWrapper(&propertiesStore{values: values}).(*kvwrapper)
+	return d.fields(dst), nil
+}
+
+func (u propertiesUnmarshaller) Name() string {
+	return "properties"
+}
+
+// UnmarshallStrict rejects duplicate keys and keys that do not match any
+// field.
+func (u propertiesUnmarshaller) UnmarshallStrict(bs []byte,
+	dst interface{}) error {
+
+	values, err := parseProperties(string(bs), true)
+	if err != nil {
+		return err
+	}
+	d := KVWrapper(&propertiesStore{values: values}).(*kvwrapper)
+	if err := checkUnknownKeys(d, dst, values); err != nil {
+		return err
+	}
+	return d.Decode(dst)
+}
+
+// parseProperties parses the content of a .properties file into a map of
+// lowercase keys, following the format of java.util.Properties: lines
+// starting with '#' or '!' are comments, a key is separated from its value
+// by '=', ':' or whitespace, a backslash at the end of a line joins it with
+// the next one, and \t, \n, \r, \f and \uXXXX escapes are expanded. If a key
+// is set more than once, the last value wins, unless failOnDup is set.
+func parseProperties(s string, failOnDup bool) (map[string]string, error) {
+	s = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(s)
+	lines := strings.Split(s, "\n")
+
+	values := map[string]string{}
+	for i := 0; i < len(lines); i++ {
+		n := i + 1
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		for continued(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if continued(line) {
+			line = line[:len(line)-1]
+		}
+
+		key, val := splitProperty(line)
+		key, err := unescapeProperty(key)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if val, err = unescapeProperty(val); err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", n, key, err)
+		}
+		if _, ok := values[strings.ToLower(key)]; ok && failOnDup {
+			return nil, fmt.Errorf("line %d: key %s already set", n, key)
+		}
+		values[strings.ToLower(key)] = val
+	}
+	return values, nil
+}
+
+// continued reports whether line ends with an odd number of backslashes,
+// i.e. continues on the next line.
+func continued(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// splitProperty splits line into the escaped key and value.
+func splitProperty(line string) (string, string) {
+	i := 0
+	for ; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			break
+		}
+	}
+	if i > len(line) {
+		i = len(line)
+	}
+	key, rest := line[:i], strings.TrimLeft(line[i:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return key, rest
+}
+
+// unescapeProperty expands the escapes of a key or a value. A backslash
+// followed by any other character stands for the character.
+func unescapeProperty(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			r, ok := parseUnicodeEscape(s[i+1:])
+			if !ok {
+				return "", fmt.Errorf("malformed \\uXXXX escape")
+			}
+			i += 4
+			// Characters outside the BMP are escaped as surrogate pairs
+			if utf16.IsSurrogate(r) && strings.HasPrefix(s[i+1:], "\\u") {
+				if r2, ok := parseUnicodeEscape(s[i+3:]); ok {
+					r = utf16.DecodeRune(r, r2)
+					i += 6
+				}
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// parseUnicodeEscape parses the 4 hex digits s starts with.
+func parseUnicodeEscape(s string) (rune, bool) {
+	if len(s) < 4 {
+		return 0, false
+	}
+	r, err := strconv.ParseUint(s[:4], 16, 16)
+	return rune(r), err == nil
+}
+
+// NewPropertiesFileDecoder returns a decoder of the required Java-style
+// .properties file. Dotted keys map to nested struct fields, e.g. the key
+// db.pool.size to Db.Pool.Size. Keys are case-insensitive and can be
+// overridden with the `properties` struct tag. The values are literal text:
+// string fields are set to them as is, other fields parse them the same way
+// env var values are.
+func NewPropertiesFileDecoder(filename string) Decoder {
+	return NewFileUnmarshaller(filename, &propertiesUnmarshaller{})
+}
+
+// NewOptionalPropertiesFileDecoder returns a decoder of the optional
+// .properties file: if the file does not exist, decoding returns an error
+// wrapping ErrSkipped.
+func NewOptionalPropertiesFileDecoder(filename string) Decoder {
+	return NewOptionalFileUnmarshaller(filename, &propertiesUnmarshaller{})
+}
@@ -0,0 +1,125 @@
+package decoders
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseProperties(t *testing.T) {
+	RegisterTestingT(t)
+
+	values, err := parseProperties(`
+# a comment
+! another comment
+db.pool.size=10
+db.host : localhost
+db.name   app
+  indented = value with spaces  
+empty=
+Mixed.Case=1
+fruits = apple, banana, \
+         pear
+ends.with.backslash = a\\
+escaped\=key\:x = \t\u00e9\uD83D\uDE00\x
+windows=crlf`+"\r\n"+`last=1`, false)
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(values).Should(Equal(map[string]string{
+		"db.pool.size":        "10",
+		"db.host":             "localhost",
+		"db.name":             "app",
+		"indented":            "value with spaces  ",
+		"empty":               "",
+		"mixed.case":          "1",
+		"fruits":              "apple, banana, pear",
+		"ends.with.backslash": `a\`,
+		"escaped=key:x":       "\té😀x",
+		"windows":             "crlf",
+		"last":                "1",
+	}))
+
+	_, err = parseProperties("a=1\nb=\\u12", false)
+	Ω(err).Should(MatchError(`line 2: b: malformed \uXXXX escape`))
+	_, err = parseProperties("a\\uxyz=1", false)
+	Ω(err).Should(MatchError(`line 1: malformed \uXXXX escape`))
+
+	values, err = parseProperties("db.size=1\nDB.Size=2", false)
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(values).Should(Equal(map[string]string{"db.size": "2"}))
+	_, err = parseProperties("db.size=1\nDB.Size=2", true)
+	Ω(err).Should(MatchError("line 2: key DB.Size already set"))
+}
+
+func TestNewPropertiesFileDecoder(t *testing.T) {
+	RegisterTestingT(t)
+
+	filename := filepath.Join(t.TempDir(), "app.properties")
+	Ω(ioutil.WriteFile(filename, []byte(`
+db.pool.size=10
+db.pool.timeout=30s
+db.hosts=[a, b]
+db.userName=admin
+app.name=test
+`), 0644)).Should(Succeed())
+
+	type Cfg struct {
+		DB struct {
+			Pool struct {
+				Size    int
+				Timeout string
+			}
+			Hosts    []string
+			UserName string
+		}
+		Name string `properties:"app.name"`
+	}
+
+	var v Cfg
+	d := NewPropertiesFileDecoder(filename)
+	Ω(d.Decode(&v)).Should(Succeed())
+	Ω(v.DB.Pool.Size).Should(Equal(10))
+	Ω(v.DB.Pool.Timeout).Should(Equal("30s"))
+	Ω(v.DB.Hosts).Should(Equal([]string{"a", "b"}))
+	Ω(v.DB.UserName).Should(Equal("admin"))
+	Ω(v.Name).Should(Equal("test"))
+	Ω(Name(d)).Should(Equal("properties file " + filename))
+
+	Ω(Strict(d).Decode(&v)).Should(Succeed())
+	var small struct {
+		DB struct {
+			Hosts []string
+		}
+	}
+	Ω(Strict(d).Decode(&small)).Should(MatchError(filename + ": " +
+		"unknown fields: app.name, db.pool.size, db.pool.timeout, db.username"))
+
+	Ω(ioutil.WriteFile(filename, []byte("db.pool.size=1\ndb.pool.size=2\n"),
+		0644)).Should(Succeed())
+	Ω(d.Decode(&v)).Should(Succeed())
+	Ω(v.DB.Pool.Size).Should(Equal(2))
+	Ω(Strict(d).Decode(&v)).Should(MatchError(filename + ": " +
+		"line 2: key db.pool.size already set"))
+
+	Ω(ioutil.WriteFile(filename, []byte("db.pool.size=ten\n"), 0644)).
+		Should(Succeed())
+	Ω(d.Decode(&v)).Should(MatchError(filename + ": db.pool.size: " +
+		"cannot parse as int"))
+
+	// Values are literal text, even if they would be special in YAML
+	Ω(ioutil.WriteFile(filename, []byte("msg=Hello: world\npass=#secret\n"+
+		"quoted='a'\n"), 0644)).Should(Succeed())
+	var text struct {
+		Msg    string
+		Pass   string
+		Quoted string
+	}
+	Ω(d.Decode(&text)).Should(Succeed())
+	Ω(text.Msg).Should(Equal("Hello: world"))
+	Ω(text.Pass).Should(Equal("#secret"))
+	Ω(text.Quoted).Should(Equal("'a'"))
+
+	d = NewOptionalPropertiesFileDecoder(filepath.Join(t.TempDir(), "x"))
+	Ω(d.Decode(&v)).Should(MatchError(ErrSkipped))
+}
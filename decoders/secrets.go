@@ -0,0 +1,95 @@
+package decoders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// Redacted replaces the values of secret fields in the errors of the file
+// decoders, see WithSecrets, and in the help text of the flag decoders.
+const Redacted = "[REDACTED]"
+
+// IsSecret reports whether the value of sf must not be printed: sf is either
+// tagged as `config:",secret"` or a string type that formats its non-empty
+// values as Redacted, e.g. config.Secret.
+func IsSecret(sf reflect.StructField) bool {
+	opts := strings.Split(sf.Tag.Get("config"), ",")
+	for _, opt := range opts[1:] {
+		if opt == "secret" {
+			return true
+		}
+	}
+
+	t := reflectx.Deref(sf.Type)
+	if t.Kind() != reflect.String {
+		return false
+	}
+	v := reflect.New(t).Elem()
+	v.SetString("x")
+	s, ok := v.Interface().(fmt.Stringer)
+	return ok && s.String() == Redacted
+}
+
+type secretsKey struct{}
+
+// WithSecrets returns a copy of ctx that makes the file decoders running with
+// it replace the values of the fields of the destination that secret reports,
+// given by their index, with Redacted in their errors, e.g. the `abc123` of
+// "cannot unmarshal !!str `abc123` into int". Only the YAML, JSON, TOML and
+// HCL decoders echo raw values in their errors; the other decoders report
+// the values as ValueErrors.
+func WithSecrets(ctx context.Context,
+	secret func(index []int) bool) context.Context {
+
+	return context.WithValue(ctx, secretsKey{}, secret)
+}
+
+// redactSecrets returns err, the error of u decoding bs into dst, with the
+// values of the secret fields of dst replaced, see WithSecrets.
+func redactSecrets(ctx context.Context, u Unmarshaller, bs []byte,
+	dst interface{}, err error) error {
+
+	secret, ok := ctx.Value(secretsKey{}).(func(index []int) bool)
+	du, isDoc := u.(docUnmarshaller)
+	if !ok || !isDoc {
+		return err
+	}
+	doc, derr := du.unmarshallDoc(bs)
+	if derr != nil {
+		return err
+	}
+
+	values := []string{}
+	du.format().walk(doc, reflect.TypeOf(dst), []int{},
+		func(index []int, v interface{}) {
+			if v == nil || !secret(index) {
+				return
+			}
+			switch reflect.TypeOf(v).Kind() {
+			case reflect.Map, reflect.Slice:
+				return
+			}
+			if s := fmt.Sprint(v); s != "" {
+				values = append(values, s)
+			}
+		})
+	// Replace the longest values first, they may contain the shorter ones
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+
+	msg := err.Error()
+	for _, v := range values {
+		msg = strings.ReplaceAll(msg, v, Redacted)
+	}
+	if msg == err.Error() {
+		return err
+	}
+	return errors.New(msg)
+}
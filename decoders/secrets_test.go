@@ -0,0 +1,48 @@
+package decoders
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWithSecrets(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Port int
+		Pin  int
+		Key  struct {
+			ID  int
+			Pin int
+		}
+	}
+	secret := func(index []int) bool {
+		return index[len(index)-1] == 1
+	}
+	ctx := WithSecrets(context.Background(), secret)
+
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	Ω(ioutil.WriteFile(filename, []byte("port: 80\npin: abcd\n"+
+		"key:\n  id: x\n  pin: abcdef\n"), 0644)).Should(Succeed())
+	d := NewYamlFileDecoder(filename)
+	var v Cfg
+	Ω(DecodeContext(ctx, d, &v)).Should(MatchError(filename + ": " +
+		"yaml: unmarshal errors:\n" +
+		"  line 2: cannot unmarshal !!str `[REDACTED]` into int\n" +
+		"  line 4: cannot unmarshal !!str `x` into int\n" +
+		"  line 5: cannot unmarshal !!str `[REDACTED]` into int"))
+
+	// Without WithSecrets the error is left intact
+	Ω(d.Decode(&v)).Should(MatchError(ContainSubstring("`abcdef`")))
+
+	// The other decoders report values as ValueErrors
+	filename = filepath.Join(t.TempDir(), "config.ini")
+	Ω(ioutil.WriteFile(filename, []byte("pin = abcd\n"), 0644)).
+		Should(Succeed())
+	Ω(DecodeContext(ctx, NewIniFileDecoder(filename), &v)).Should(
+		MatchError(filename + ": pin: cannot parse as int"))
+}
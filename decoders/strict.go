@@ -0,0 +1,65 @@
+package decoders
+
+import "context"
+
+// StrictUnmarshaller is implemented by unmarshallers that can reject unknown
+// fields and duplicate keys.
+type StrictUnmarshaller interface {
+	UnmarshallStrict([]byte, interface{}) error
+}
+
+type strictKey struct{}
+
+// WithStrict returns a copy of ctx that makes the decoders run with it in
+// strict mode: file decoders reject unknown fields and duplicate keys if
+// their unmarshaller implements StrictUnmarshaller.
+func WithStrict(ctx context.Context) context.Context {
+	return context.WithValue(ctx, strictKey{}, true)
+}
+
+// IsStrict reports whether ctx enables strict mode.
+func IsStrict(ctx context.Context) bool {
+	strict, _ := ctx.Value(strictKey{}).(bool)
+	return strict
+}
+
+type strictDecoder struct {
+	d Decoder
+}
+
+func (s strictDecoder) Decode(dst interface{}) error {
+	return s.DecodeContext(context.Background(), dst)
+}
+
+func (s strictDecoder) DecodeContext(ctx context.Context,
+	dst interface{}) error {
+
+	return DecodeContext(WithStrict(ctx), s.d, dst)
+}
+
+func (s strictDecoder) Source(dst interface{}, index []int) string {
+	if src, ok := s.d.(Sourcer); ok {
+		return src.Source(dst, index)
+	}
+	return ""
+}
+
+func (s strictDecoder) Files() []string {
+	if f, ok := s.d.(Filer); ok {
+		return f.Files()
+	}
+	return nil
+}
+
+func (s strictDecoder) Name() string {
+	return "strict " + Name(s.d)
+}
+
+func (s strictDecoder) Kind() string {
+	return Kind(s.d)
+}
+
+// Strict returns a decoder that runs d in strict mode, see WithStrict.
+func Strict(d Decoder) Decoder {
+	return &strictDecoder{d: d}
+}
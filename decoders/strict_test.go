@@ -0,0 +1,72 @@
+package decoders
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestStrict(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+		Nested struct {
+			Str string
+		}
+	}
+
+	dir := t.TempDir()
+	writeFile := func(name, s string) string {
+		name = filepath.Join(dir, name)
+		Ω(ioutil.WriteFile(name, []byte(s), 0644)).Should(Succeed())
+		return name
+	}
+
+	unknownYaml := writeFile("unknown.yaml", "number: 1\nnested:\n  sttr: a\n")
+	dupYaml := writeFile("dup.yaml", "number: 1\nnested:\n  str: a\nnumber: 2\n")
+	unknownJson := writeFile("unknown.json",
+		`{"number": 1, "nested": {"sttr": "a"}}`)
+	dupJson := writeFile("dup.json",
+		"{\"number\": 1,\n\"nested\": {\"str\": \"a\",\n\"str\": \"b\"}}")
+
+	var dst Cfg
+	for _, d := range []Decoder{
+		NewYamlFileDecoder(unknownYaml),
+		NewYamlFileDecoder(dupYaml),
+		NewJsonFileDecoder(unknownJson),
+		NewJsonFileDecoder(dupJson),
+	} {
+		Ω(d.Decode(&dst)).Should(Succeed())
+	}
+
+	err := Strict(NewYamlFileDecoder(unknownYaml)).Decode(&dst)
+	Ω(err).Should(MatchError(ContainSubstring(unknownYaml + ": ")))
+	Ω(err).Should(MatchError(ContainSubstring("line 3: field sttr not found")))
+
+	err = Strict(NewYamlFileDecoder(dupYaml)).Decode(&dst)
+	Ω(err).Should(MatchError(ContainSubstring(
+		"line 4: field number already set")))
+
+	err = Strict(NewJsonFileDecoder(unknownJson)).Decode(&dst)
+	Ω(err).Should(MatchError(
+		unknownJson + `: json: unknown field "sttr"`))
+
+	err = Strict(NewJsonFileDecoder(dupJson)).Decode(&dst)
+	Ω(err).Should(MatchError(
+		dupJson + `: line 3: duplicate key "nested.str"`))
+
+	// The strict mode can be enabled by the context as well
+	err = DecodeContext(WithStrict(context.Background()),
+		NewOptionalJsonFileDecoder(dupJson), &dst)
+	Ω(err).Should(MatchError(ContainSubstring("duplicate key")))
+
+	// The wrapped decoder keeps its source and files
+	d := Strict(NewYamlFileDecoder(unknownYaml))
+	Ω(d.(Sourcer).Source(&dst, []int{0})).Should(Equal(unknownYaml))
+	Ω(d.(Filer).Files()).Should(Equal([]string{unknownYaml}))
+	Ω(Strict(NewEnvDecoder("")).(Filer).Files()).Should(BeEmpty())
+}
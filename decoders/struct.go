@@ -19,6 +19,15 @@ func (s structStore) DecodeKey(key string, dst interface{}) error {
 	return nil
 }
 
+func (s structStore) HasKey(key string) bool {
+	_, ok := s.fieldMap[key]
+	return ok
+}
+
+func (s structStore) Name() string {
+	return "struct"
+}
+
 func (s structStore) Tagname() string {
 	return s.dstTagname
 }
@@ -0,0 +1,74 @@
+package decoders
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+type tomlUnmarshaller struct{}
+
+func (u tomlUnmarshaller) Unmarshall(bs []byte, dst interface{}) error {
+	return toml.Unmarshal(bs, dst)
+}
+
+var tomlFormat = docFormat{
+	tagname:        "toml",
+	fold:           true,
+	inlineEmbedded: true,
+}
+
+func (u tomlUnmarshaller) Fields(bs []byte, dst interface{}) ([][]int, error) {
+	return docFields(u, bs, dst)
+}
+
+func (u tomlUnmarshaller) unmarshallDoc(bs []byte) (interface{}, error) {
+	var doc interface{}
+	err := toml.Unmarshal(bs, &doc)
+	return doc, err
+}
+
+func (u tomlUnmarshaller) format() docFormat {
+	return tomlFormat
+}
+
+func (u tomlUnmarshaller) Name() string {
+	return "toml"
+}
+
+// UnmarshallStrict rejects keys that do not match any field. TOML forbids
+// duplicate keys, so they are rejected in both modes.
+func (u tomlUnmarshaller) UnmarshallStrict(bs []byte, dst interface{}) error {
+	md, err := toml.Decode(string(bs), dst)
+	if err != nil {
+		return err
+	}
+
+	// Only report the outermost unknown keys, not the keys nested in them
+	unknown := map[string]bool{}
+	keys := []string{}
+	for _, k := range md.Undecoded() {
+		if unknown[k.String()] || unknown[k[:len(k)-1].String()] {
+			unknown[k.String()] = true
+			continue
+		}
+		unknown[k.String()] = true
+		keys = append(keys, k.String())
+	}
+	if len(keys) > 0 {
+		return fmt.Errorf("unknown fields: %s", strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// NewTomlFileDecoder returns a decoder of the required TOML file.
+func NewTomlFileDecoder(filename string) Decoder {
+	return NewFileUnmarshaller(filename, &tomlUnmarshaller{})
+}
+
+// NewOptionalTomlFileDecoder returns a decoder of the optional TOML file: if
+// the file does not exist, decoding returns an error wrapping ErrSkipped.
+func NewOptionalTomlFileDecoder(filename string) Decoder {
+	return NewOptionalFileUnmarshaller(filename, &tomlUnmarshaller{})
+}
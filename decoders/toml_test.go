@@ -0,0 +1,128 @@
+package decoders
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	. "github.com/onsi/gomega"
+)
+
+func TestNewTomlFileDecoder(t *testing.T) {
+	t.Run("interface", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		var v interface{}
+		d := NewTomlFileDecoder("./config_test.toml")
+		Ω(d.Decode(&v)).Should(BeNil())
+
+		expV := map[string]interface{}{
+			"simple": map[string]interface{}{
+				"num":  int64(1),
+				"str":  "test text",
+				"arr":  []interface{}{"a", "b", "d"},
+				"bool": true,
+			},
+			"nested": map[string]interface{}{
+				"one": map[string]interface{}{
+					"two": []map[string]interface{}{
+						{
+							"three":  int64(3),
+							"common": "c-3",
+						},
+						{
+							"four":   int64(4),
+							"common": "c-4",
+						},
+					},
+					"five": []interface{}{int64(6), int64(7), int64(8)},
+				},
+				"done": "we are done",
+			},
+			"names": map[string]interface{}{
+				"lowercase":   "just a lowercase key",
+				"Capitalized": "a Capitalized key",
+				"camelCase":   "a camelCase key",
+				"PascalCase":  "PascalCase is cool",
+				"snake_case":  "try snake_case",
+				"kebab-case":  "kebab-case is always a pleasure to look at",
+			},
+		}
+
+		Ω(cmp.Diff(v, expV)).Should(BeEmpty())
+		Ω(v).Should(Equal(expV))
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		var v testTomlConfig
+		d := NewTomlFileDecoder("./config_test.toml")
+		Ω(d.Decode(&v)).Should(BeNil())
+
+		expV := testTomlConfig{
+			Simple: testYamlConfig_Simple{
+				Bool: true,
+				Num:  1,
+				Str:  "test text",
+				Arr:  []string{"a", "b", "d"},
+			},
+			Nested: testYamlConfig_Nested{
+				One: testYamlConfig_Nested_One{
+					Two: []testYamlConfig_Nested_One_Two{
+						{Three: 3, Common: "c-3"},
+						{Four: 4, Common: "c-4"},
+					},
+					Five: []int{6, 7, 8},
+				},
+				Done: "we are done",
+			},
+			Names: testTomlConfig_Names{
+				Lowercase:   "just a lowercase key",
+				Capitalized: "a Capitalized key",
+				CamelCase:   "a camelCase key",
+				PascalCase:  "PascalCase is cool",
+				SnakeCase:   "try snake_case",
+				KebabCase:   "kebab-case is always a pleasure to look at",
+			},
+		}
+
+		Ω(cmp.Diff(v, expV)).Should(BeEmpty())
+		Ω(v).Should(Equal(expV))
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		RegisterTestingT(t)
+
+		var v struct {
+			Simple struct {
+				Bool bool
+				Num  int
+				Str  string
+			}
+		}
+		d := Strict(NewTomlFileDecoder("./config_test.toml"))
+		Ω(d.Decode(&v)).Should(MatchError("./config_test.toml: " +
+			"unknown fields: simple.arr, nested, names"))
+		Ω(v.Simple.Num).Should(Equal(1))
+
+		var c testTomlConfig
+		Ω(d.Decode(&c)).Should(Succeed())
+	})
+}
+
+// testTomlConfig shares the fixture of testYamlConfig, only the explicitly
+// named keys need toml tags.
+type testTomlConfig struct {
+	Simple testYamlConfig_Simple
+	Nested testYamlConfig_Nested
+	Names  testTomlConfig_Names
+}
+
+type testTomlConfig_Names struct {
+	Lowercase   string
+	Capitalized string `toml:"Capitalized"`
+	CamelCase   string `toml:"camelCase"`
+	PascalCase  string `toml:"PascalCase"`
+	SnakeCase   string `toml:"snake_case"`
+	KebabCase   string `toml:"kebab-case"`
+}
@@ -1,6 +1,10 @@
 package decoders
 
-import "gopkg.in/yaml.v2"
+import (
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
 
 type yamlUnmarshaller struct{}
 
@@ -8,6 +12,41 @@ func (u yamlUnmarshaller) Unmarshall(bs []byte, dst interface{}) error {
 	return yaml.Unmarshal(bs, dst)
 }
 
+var yamlFormat = docFormat{
+	tagname: "yaml",
+	mapFunc: strings.ToLower,
+	inline:  []string{"inline"},
+}
+
+func (u yamlUnmarshaller) Fields(bs []byte, dst interface{}) ([][]int, error) {
+	return docFields(u, bs, dst)
+}
+
+func (u yamlUnmarshaller) unmarshallDoc(bs []byte) (interface{}, error) {
+	var doc interface{}
+	err := yaml.Unmarshal(bs, &doc)
+	return doc, err
+}
+
+func (u yamlUnmarshaller) format() docFormat {
+	return yamlFormat
+}
+
+func (u yamlUnmarshaller) Name() string {
+	return "yaml"
+}
+
+func (u yamlUnmarshaller) UnmarshallStrict(bs []byte, dst interface{}) error {
+	return yaml.UnmarshalStrict(bs, dst)
+}
+
+// NewYamlFileDecoder returns a decoder of the required YAML file.
 func NewYamlFileDecoder(filename string) Decoder {
 	return NewFileUnmarshaller(filename, &yamlUnmarshaller{})
 }
+
+// NewOptionalYamlFileDecoder returns a decoder of the optional YAML file: if
+// the file does not exist, decoding returns an error wrapping ErrSkipped.
+func NewOptionalYamlFileDecoder(filename string) Decoder {
+	return NewOptionalFileUnmarshaller(filename, &yamlUnmarshaller{})
+}
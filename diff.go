@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// Change describes a config key whose value would change on the next load.
+type Change struct {
+	Key string
+	Old interface{}
+	New interface{}
+}
+
+// Diff runs the decoders of the flow on a fresh copy of the defaults given to
+// NewFlow and returns the keys whose values differ from the ones in
+// Flow.Config, sorted by key, along with the errors of the load. Flow.Config
+// is left intact and the BeforeDecoder, AfterDecoder and AfterLoad hooks are
+// not called, so the changes they would make are not reported.
+//
+// The non-zero values of secret fields are replaced with Redacted.
+func (f *Flow) Diff() ([]Change, Errors) {
+	f.mutex.Lock()
+	cur := reflectx.Copy(f.Config)
+	f.mutex.Unlock()
+
+	next := reflectx.Copy(f.defaults)
+	r := f.loadInto(context.Background(), next, false, loadHooks{})
+
+	t := reflectx.Deref(reflect.TypeOf(next))
+	tm := f.typeMap(t)
+	oldFields := f.fieldMap(reflect.ValueOf(cur))
+	newFields := f.fieldMap(reflect.ValueOf(next))
+
+	keys := make([]string, 0, len(tm))
+	for key := range tm {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	changes := []Change{}
+	for _, key := range keys {
+		o, n := oldFields[key], newFields[key]
+		if o.IsValid() == n.IsValid() &&
+			(!o.IsValid() || reflect.DeepEqual(o.Interface(), n.Interface())) {
+			continue
+		}
+		secret := isSecret(t.FieldByIndex(tm[key]))
+		changes = append(changes, Change{
+			Key: key,
+			Old: printable(o, secret),
+			New: printable(n, secret),
+		})
+	}
+	return changes, r.errs
+}
+
+// printable returns the value of v, unless v is a non-zero secret. It returns
+// nil for the zero Value, i.e. a field behind a nil pointer.
+func printable(v reflect.Value, secret bool) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if secret && !v.IsZero() {
+		return Redacted
+	}
+	return v.Interface()
+}
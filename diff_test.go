@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiff(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number   int
+		Flag     bool
+		Str1     string
+		Str2     string
+		Password Secret
+		Pin      int `config:",secret"`
+		Embedded struct {
+			N int
+		}
+	}
+
+	os.Setenv("PASSWORD", "new-pwd")
+	os.Setenv("EMBEDDED_N", "5")
+	defer os.Unsetenv("PASSWORD")
+	defer os.Unsetenv("EMBEDDED_N")
+
+	actual := Cfg{Number: -123, Flag: true, Str1: "qwe", Pin: 1234}
+	f := NewFlow(&actual,
+		decoders.NewYamlFileDecoder("test.yml"),
+		decoders.NewEnvDecoder(""),
+	)
+
+	changes, errs := f.Diff()
+	Ω(errs).Should(BeEmpty())
+	Ω(changes).Should(Equal([]Change{
+		{Key: "Embedded.N", Old: 0, New: 5},
+		{Key: "Number", Old: -123, New: 987654321},
+		{Key: "Password", Old: Secret(""), New: Redacted},
+		{Key: "Str1", Old: "qwe", New: "aasd"},
+	}))
+	Ω(actual).Should(Equal(Cfg{Number: -123, Flag: true, Str1: "qwe",
+		Pin: 1234}))
+
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	changes, errs = f.Diff()
+	Ω(errs).Should(BeEmpty())
+	Ω(changes).Should(BeEmpty())
+
+	f.Config.(*Cfg).Pin = 0
+	f.Config.(*Cfg).Str2 = "changed"
+	changes, _ = f.Diff()
+	Ω(changes).Should(Equal([]Change{
+		{Key: "Pin", Old: 0, New: Redacted},
+		{Key: "Str2", Old: "changed", New: ""},
+	}))
+
+	os.Setenv("EMBEDDED_N", "abc")
+	changes, errs = f.Diff()
+	Ω(errs).Should(MatchError(
+		"env EMBEDDED_N=abc: cannot parse as int for field Embedded.N"))
+	Ω(changes).ShouldNot(BeEmpty())
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	"github.com/PlanitarInc/go-config/reflectx"
+	"gopkg.in/yaml.v2"
+)
+
+// Formats supported by Flow.Dump.
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Redacted replaces the values of secret fields in the output of the flow.
+const Redacted = decoders.Redacted
+
+// Dump writes Flow.Config to w in the given format, using the same key names
+// the YAML and JSON file decoders read, so the output can be decoded back.
+//
+// The values of secret fields, i.e. Secret fields and the ones tagged as
+// `config:",secret"`, are replaced: strings with Redacted, other types with
+// their zero value.
+func (f *Flow) Dump(format string, w io.Writer) error {
+	f.mutex.Lock()
+	cfg := reflectx.Copy(f.Config)
+	f.mutex.Unlock()
+
+	f.redact(cfg)
+
+	var bs []byte
+	var err error
+	switch format {
+	case FormatYAML:
+		bs, err = yaml.Marshal(cfg)
+	case FormatJSON:
+		bs, err = json.MarshalIndent(cfg, "", "  ")
+		bs = append(bs, '\n')
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(bs)
+	return err
+}
+
+// redact replaces the values of the secret fields of cfg.
+func (f *Flow) redact(cfg interface{}) {
+	v := reflect.ValueOf(cfg)
+	t := reflect.Indirect(v).Type()
+	tm := f.typeMap(t)
+	for key, field := range f.fieldMap(v) {
+		if !isSecret(t.FieldByIndex(tm[key])) {
+			continue
+		}
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.String && field.Len() > 0 {
+			field.SetString(Redacted)
+		} else {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+}
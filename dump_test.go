@@ -0,0 +1,95 @@
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+type dumpCfg struct {
+	Number   int
+	Password string  `config:",secret"`
+	Empty    string  `config:",secret"`
+	Pin      int     `config:",secret"`
+	Token    *string `config:",secret"`
+	Server   struct {
+		Host string
+		Port int `yaml:"server_port" json:"serverPort"`
+	}
+	Hosts []string
+}
+
+func TestDump(t *testing.T) {
+	RegisterTestingT(t)
+
+	token := "tkn"
+	actual := dumpCfg{Number: 5, Password: "pwd", Pin: 1234, Token: &token}
+	actual.Server.Host = "localhost"
+	actual.Server.Port = 8080
+	actual.Hosts = []string{"a", "b"}
+	f := NewFlow(&actual)
+
+	var buf bytes.Buffer
+	Ω(f.Dump(FormatYAML, &buf)).Should(Succeed())
+	Ω(buf.String()).Should(Equal(`number: 5
+password: '[REDACTED]'
+empty: ""
+pin: 0
+token: '[REDACTED]'
+server:
+  host: localhost
+  server_port: 8080
+hosts:
+- a
+- b
+`))
+
+	// The output can be decoded back
+	exp := actual
+	exp.Password = Redacted
+	exp.Pin = 0
+	redacted := Redacted
+	exp.Token = &redacted
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "dump.yaml")
+	Ω(ioutil.WriteFile(filename, buf.Bytes(), 0644)).Should(Succeed())
+	loaded := dumpCfg{}
+	Ω(decoders.NewYamlFileDecoder(filename).Decode(&loaded)).Should(Succeed())
+	Ω(loaded).Should(Equal(exp))
+
+	buf.Reset()
+	Ω(f.Dump(FormatJSON, &buf)).Should(Succeed())
+	Ω(buf.String()).Should(Equal(`{
+  "Number": 5,
+  "Password": "[REDACTED]",
+  "Empty": "",
+  "Pin": 0,
+  "Token": "[REDACTED]",
+  "Server": {
+    "Host": "localhost",
+    "serverPort": 8080
+  },
+  "Hosts": [
+    "a",
+    "b"
+  ]
+}
+`))
+
+	filename = filepath.Join(dir, "dump.json")
+	Ω(ioutil.WriteFile(filename, buf.Bytes(), 0644)).Should(Succeed())
+	loaded = dumpCfg{}
+	Ω(decoders.NewJsonFileDecoder(filename).Decode(&loaded)).Should(Succeed())
+	Ω(loaded).Should(Equal(exp))
+
+	// The config itself is intact
+	Ω(actual.Password).Should(Equal("pwd"))
+	Ω(*actual.Token).Should(Equal("tkn"))
+
+	Ω(f.Dump("toml", &buf)).Should(MatchError(`unknown format "toml"`))
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// LoadError describes a failure of a decoder of a flow. If the failure is
+// specific to a key, the message starts with the kind of the decoder and the
+// key, e.g. "env APP_PORT=abc: ...", see decoders.Kind, preceded by the file
+// of file decoders, e.g. "config.ini: ini server.port=abc: ...".
+type LoadError struct {
+	Decoder decoders.Decoder
+	// Key is the key the decoder failed to decode, as mapped by the decoder,
+	// e.g. an env var name. It is empty if the failure is not specific to a
+	// single key, e.g. a file cannot be read.
+	Key string
+	// Field is the config key of the field the decoder failed to set, e.g.
+	// "Server.Port".
+	Field string
+	// Value is the raw value of the key, if known. The values of secret
+	// fields are replaced with Redacted.
+	Value string
+	Err   error
+}
+
+func (e *LoadError) Error() string {
+	if e.Key == "" {
+		return e.Err.Error()
+	}
+	s := e.Key
+	if e.Decoder != nil {
+		s = decoders.Kind(e.Decoder) + " " + s
+		if f, ok := e.Decoder.(decoders.Filer); ok {
+			if files := f.Files(); len(files) == 1 {
+				s = files[0] + ": " + s
+			}
+		}
+	}
+	if e.Value != "" {
+		s += "=" + e.Value
+	}
+	s += ": " + e.Err.Error()
+	if e.Field != "" {
+		s += " for field " + e.Field
+	}
+	return s
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// Errors lists the errors of a load. errors.Is and errors.As match an Errors
+// value if they match any of the errors in the list.
+type Errors []error
+
+func (es Errors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (es Errors) Is(target error) bool {
+	for _, e := range es {
+		if errors.Is(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (es Errors) As(target interface{}) bool {
+	for _, e := range es {
+		if errors.As(e, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadError wraps err returned by d when decoding cfg into a LoadError.
+func (f *Flow) loadError(cfg interface{}, d decoders.Decoder,
+	err error) *LoadError {
+
+	le := &LoadError{Decoder: d, Err: err}
+
+	var ke *decoders.KeyError
+	if !errors.As(err, &ke) {
+		return le
+	}
+	le.Key = ke.Key
+	le.Field = f.keyOf(cfg, ke.Index)
+	le.Err = ke.Err
+
+	var ve *decoders.ValueError
+	if errors.As(ke.Err, &ve) {
+		le.Value = ve.Value
+		t := reflectx.Deref(reflect.TypeOf(cfg))
+		if isSecret(t.FieldByIndex(ke.Index)) {
+			le.Value = Redacted
+		}
+	}
+	return le
+}
+
+// keyOf returns the config key of the field at index of cfg.
+func (f *Flow) keyOf(cfg interface{}, index []int) string {
+	t := reflectx.Deref(reflect.TypeOf(cfg))
+	for key, idx := range f.typeMap(t) {
+		if reflect.DeepEqual(idx, index) {
+			return key
+		}
+	}
+	return ""
+}
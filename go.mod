@@ -3,7 +3,9 @@ module github.com/PlanitarInc/go-config
 go 1.17
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/google/go-cmp v0.5.6
+	github.com/hashicorp/hcl v1.0.0
 	github.com/onsi/gomega v1.16.0
 	gopkg.in/yaml.v2 v2.4.0
 )
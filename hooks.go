@@ -0,0 +1,136 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// Defaulter is implemented by config structs, and nested sections, that set
+// their own defaults. NewFlow calls SetDefaults on the defaults it is given.
+type Defaulter interface {
+	SetDefaults()
+}
+
+// Normalizer is implemented by config structs, and nested sections, that
+// normalize their values after decoding, e.g. trim or lowercase strings.
+// Normalize is called after all the decoders of a flow, before validation;
+// nested sections are normalized before the structs containing them.
+type Normalizer interface {
+	Normalize()
+}
+
+// Validator is implemented by config structs, and nested sections, that
+// validate themselves. Validate is called after the `validate` tags are
+// checked, its errors are reported as ValidationErrors.
+type Validator interface {
+	Validate() error
+}
+
+// BeforeDecoder registers fn to be called before every decoder of the flow
+// runs on cfg.
+func (f *Flow) BeforeDecoder(fn func(d decoders.Decoder, cfg interface{})) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.beforeFns = append(f.beforeFns, fn)
+}
+
+// AfterDecoder registers fn to be called after every decoder of the flow ran
+// on cfg, err is the error of the decoder, if any.
+func (f *Flow) AfterDecoder(
+	fn func(d decoders.Decoder, cfg interface{}, err error)) {
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.afterFns = append(f.afterFns, fn)
+}
+
+// AfterLoad registers fn to be called at the end of every load, after the
+// config was validated. The errors of fn are reported as load errors.
+func (f *Flow) AfterLoad(fn func(cfg interface{}) error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.afterLoadFns = append(f.afterLoadFns, fn)
+}
+
+// setDefaults calls SetDefaults of every Defaulter section of cfg.
+func setDefaults(cfg interface{}) {
+	walkSections(cfg, func(key string, s interface{}) {
+		if d, ok := s.(Defaulter); ok {
+			d.SetDefaults()
+		}
+	})
+}
+
+// normalize calls Normalize of every Normalizer section of cfg.
+func normalize(cfg interface{}) {
+	walkSections(cfg, func(key string, s interface{}) {
+		if n, ok := s.(Normalizer); ok {
+			n.Normalize()
+		}
+	})
+}
+
+// validateSections calls Validate of every Validator section of cfg.
+func validateSections(cfg interface{}) ValidationErrors {
+	errs := ValidationErrors{}
+	walkSections(cfg, func(key string, s interface{}) {
+		v, ok := s.(Validator)
+		if !ok {
+			return
+		}
+		if err := v.Validate(); err != nil {
+			errs = append(errs, &ValidationError{Key: key, Err: err})
+		}
+	})
+	return errs
+}
+
+// walkSections calls fn with a pointer to every struct in cfg, including cfg
+// itself, and its config key. Nested structs are visited first. Embedded
+// structs are not visited on their own: their methods are promoted to the
+// embedding struct.
+func walkSections(cfg interface{}, fn func(key string, s interface{})) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() ||
+		v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	walkStruct(v.Elem(), "", true, fn)
+}
+
+func walkStruct(v reflect.Value, key string, self bool,
+	fn func(key string, s interface{})) {
+
+	reduce := reflectx.DelimiterKeyReducer(".")
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() != reflect.Struct || !fv.CanAddr() ||
+			!fv.Addr().CanInterface() {
+			continue
+		}
+
+		if sf.Anonymous {
+			walkStruct(fv, key, false, fn)
+		} else {
+			walkStruct(fv, reduce(key, sf.Name), true, fn)
+		}
+	}
+
+	if self {
+		fn(key, v.Addr().Interface())
+	}
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+type hookServer struct {
+	Host string
+	Port int
+}
+
+func (s *hookServer) SetDefaults() {
+	if s.Port == 0 {
+		s.Port = 80
+	}
+}
+
+func (s *hookServer) Normalize() {
+	s.Host = strings.ToLower(s.Host)
+}
+
+func (s *hookServer) Validate() error {
+	if s.Host == "" {
+		return errors.New("host is required")
+	}
+	return nil
+}
+
+type hookLogging struct {
+	Level    string
+	defaults int
+}
+
+func (l *hookLogging) SetDefaults() {
+	l.Level = "info"
+	l.defaults++
+}
+
+type hookCfg struct {
+	hookLogging
+	Name   string
+	Server hookServer
+	Backup *hookServer
+}
+
+func (c *hookCfg) Validate() error {
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+func TestLifecycleInterfaces(t *testing.T) {
+	RegisterTestingT(t)
+
+	os.Setenv("SERVER_HOST", "Example.COM")
+	defer os.Unsetenv("SERVER_HOST")
+
+	actual := hookCfg{Backup: &hookServer{Port: 8080}}
+	f := NewFlow(&actual, decoders.NewEnvDecoder(""))
+	Ω(actual.Level).Should(Equal("info"))
+	Ω(actual.defaults).Should(Equal(1))
+	Ω(actual.Server.Port).Should(Equal(80))
+	Ω(actual.Backup.Port).Should(Equal(8080))
+
+	errs := f.Load()
+	Ω(errs).Should(HaveLen(1))
+	Ω(errs).Should(MatchError("invalid config: " +
+		"Backup: host is required; name is required"))
+	Ω(f.Config.(*hookCfg).Server.Host).Should(Equal("example.com"))
+
+	os.Setenv("NAME", "app")
+	defer os.Unsetenv("NAME")
+	actual = hookCfg{Backup: &hookServer{Host: "backup"}}
+	f = NewFlow(&actual, decoders.NewEnvDecoder(""))
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+}
+
+func TestFlowHooks(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+	}
+
+	e1 := errors.New("1")
+	cd := &CountDecoder{}
+	fd := FailDecoder{e1}
+
+	calls := []string{}
+	actual := Cfg{}
+	f := NewFlow(&actual, cd, fd)
+	f.BeforeDecoder(func(d decoders.Decoder, cfg interface{}) {
+		Ω(cfg).Should(BeAssignableToTypeOf(&actual))
+		if d == cd {
+			calls = append(calls, "before count")
+		} else {
+			calls = append(calls, "before fail")
+		}
+	})
+	f.AfterDecoder(func(d decoders.Decoder, cfg interface{}, err error) {
+		if err == nil {
+			calls = append(calls, "after ok")
+		} else {
+			calls = append(calls, "after "+err.Error())
+		}
+	})
+	f.AfterLoad(func(cfg interface{}) error {
+		calls = append(calls, "after load")
+		cfg.(*Cfg).Number = 5
+		return errors.New("post-processing failed")
+	})
+
+	errs := f.Load()
+	Ω(errs).Should(MatchError("1; post-processing failed"))
+	Ω(calls).Should(Equal([]string{
+		"before count", "after ok",
+		"before fail", "after 1",
+		"after load",
+	}))
+	Ω(f.Config.(*Cfg).Number).Should(Equal(5))
+
+	// Diff is a dry run, it does not call the hooks
+	calls = []string{}
+	_, errs = f.Diff()
+	Ω(errs).Should(MatchError("1"))
+	Ω(calls).Should(BeEmpty())
+}
+
+func TestFlowHooksConcurrentLoad(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+	}
+
+	f := NewFlow(&Cfg{}, &CountDecoder{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			f.Load()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		f.BeforeDecoder(func(d decoders.Decoder, cfg interface{}) {})
+		f.AfterDecoder(func(d decoders.Decoder, cfg interface{}, err error) {})
+		f.AfterLoad(func(cfg interface{}) error { return nil })
+	}
+	<-done
+	Ω(f.Load()).Should(BeEmpty())
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PlanitarInc/go-config/decoders"
+)
+
+// ProfileEnv is the env var ProfileDecoders reads the active profile from,
+// unless the profile is given explicitly. The env decoders of flows never
+// report it as unused, see decoders.WithIgnoredEnv.
+var ProfileEnv = "APP_PROFILE"
+
+// ProfileDecoders returns the decoders of the config files layered for the
+// profile, in order:
+//
+//	config.yaml            the base file, required
+//	config.<profile>.yaml  the profile file, optional
+//	config.local.yaml      the local overrides, optional
+//
+// If profile is empty, the value of the ProfileEnv env var is used; if both
+// are empty, the profile file is left out.
+//
+// The files are decoded as JSON, TOML, INI, properties or HCL if base has the
+// .json, .toml, .ini, .properties or .hcl extension respectively, and as YAML
+// otherwise.
+func ProfileDecoders(base, profile string) []decoders.Decoder {
+	if profile == "" {
+		profile = os.Getenv(ProfileEnv)
+	}
+
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+
+	ds := []decoders.Decoder{fileDecoder(base, false)}
+	if profile != "" {
+		ds = append(ds, fileDecoder(stem+"."+profile+ext, true))
+	}
+	return append(ds, fileDecoder(stem+".local"+ext, true))
+}
+
+// NewProfileFlow returns a flow decoding the files of ProfileDecoders,
+// followed by ds.
+func NewProfileFlow(defaults interface{}, base, profile string,
+	ds ...decoders.Decoder) *Flow {
+
+	return NewFlow(defaults, append(ProfileDecoders(base, profile), ds...)...)
+}
+
+// fileDecoder returns a decoder of the file picked by its extension.
+func fileDecoder(filename string, optional bool) decoders.Decoder {
+	switch filepath.Ext(filename) {
+	case ".json":
+		if optional {
+			return decoders.NewOptionalJsonFileDecoder(filename)
+		}
+		return decoders.NewJsonFileDecoder(filename)
+	case ".toml":
+		if optional {
+			return decoders.NewOptionalTomlFileDecoder(filename)
+		}
+		return decoders.NewTomlFileDecoder(filename)
+	case ".ini":
+		if optional {
+			return decoders.NewOptionalIniFileDecoder(filename)
+		}
+		return decoders.NewIniFileDecoder(filename)
+	case ".properties":
+		if optional {
+			return decoders.NewOptionalPropertiesFileDecoder(filename)
+		}
+		return decoders.NewPropertiesFileDecoder(filename)
+	case ".hcl":
+		if optional {
+			return decoders.NewOptionalHclFileDecoder(filename)
+		}
+		return decoders.NewHclFileDecoder(filename)
+	default:
+		if optional {
+			return decoders.NewOptionalYamlFileDecoder(filename)
+		}
+		return decoders.NewYamlFileDecoder(filename)
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+func TestProfileDecoders(t *testing.T) {
+	RegisterTestingT(t)
+
+	os.Unsetenv(ProfileEnv)
+
+	Ω(ProfileDecoders("config.yaml", "prod")).Should(Equal([]decoders.Decoder{
+		decoders.NewYamlFileDecoder("config.yaml"),
+		decoders.NewOptionalYamlFileDecoder("config.prod.yaml"),
+		decoders.NewOptionalYamlFileDecoder("config.local.yaml"),
+	}))
+
+	Ω(ProfileDecoders("etc/app.json", "")).Should(Equal([]decoders.Decoder{
+		decoders.NewJsonFileDecoder("etc/app.json"),
+		decoders.NewOptionalJsonFileDecoder("etc/app.local.json"),
+	}))
+
+	Ω(ProfileDecoders("app.toml", "dev")).Should(Equal([]decoders.Decoder{
+		decoders.NewTomlFileDecoder("app.toml"),
+		decoders.NewOptionalTomlFileDecoder("app.dev.toml"),
+		decoders.NewOptionalTomlFileDecoder("app.local.toml"),
+	}))
+
+	Ω(ProfileDecoders("app.ini", "")).Should(Equal([]decoders.Decoder{
+		decoders.NewIniFileDecoder("app.ini"),
+		decoders.NewOptionalIniFileDecoder("app.local.ini"),
+	}))
+
+	Ω(ProfileDecoders("app.properties", "")).Should(Equal([]decoders.Decoder{
+		decoders.NewPropertiesFileDecoder("app.properties"),
+		decoders.NewOptionalPropertiesFileDecoder("app.local.properties"),
+	}))
+
+	Ω(ProfileDecoders("app.hcl", "")).Should(Equal([]decoders.Decoder{
+		decoders.NewHclFileDecoder("app.hcl"),
+		decoders.NewOptionalHclFileDecoder("app.local.hcl"),
+	}))
+
+	os.Setenv(ProfileEnv, "staging")
+	defer os.Unsetenv(ProfileEnv)
+	Ω(ProfileDecoders("config.yml", "")).Should(Equal([]decoders.Decoder{
+		decoders.NewYamlFileDecoder("config.yml"),
+		decoders.NewOptionalYamlFileDecoder("config.staging.yml"),
+		decoders.NewOptionalYamlFileDecoder("config.local.yml"),
+	}))
+}
+
+func TestProfileFlow(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+		Str1   string
+		Str2   string
+		Flag   bool
+	}
+
+	dir := t.TempDir()
+	writeFile := func(name, s string) {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(s), 0644)
+		Ω(err).Should(Succeed())
+	}
+	writeFile("config.yaml", "number: 1\nstr1: base\nstr2: base\n")
+	writeFile("config.dev.yaml", "number: 2\nstr1: dev\n")
+	writeFile("config.prod.yaml", "number: 3\n")
+	writeFile("config.local.yaml", "number: 4\n")
+
+	os.Unsetenv(ProfileEnv)
+	os.Setenv("FLAG", "true")
+	defer os.Unsetenv("FLAG")
+
+	actual := Cfg{}
+	base := filepath.Join(dir, "config.yaml")
+	f := NewProfileFlow(&actual, base, "dev", decoders.NewEnvDecoder(""))
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Config).Should(Equal(&Cfg{
+		Number: 4,
+		Str1:   "dev",
+		Str2:   "base",
+		Flag:   true,
+	}))
+
+	actual = Cfg{}
+	f = NewProfileFlow(&actual, base, "qa")
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Config).Should(Equal(&Cfg{Number: 4, Str1: "base", Str2: "base"}))
+	Ω(f.Skipped()).Should(HaveLen(1))
+
+	// The profile env var is not an unused env var
+	os.Setenv(ProfileEnv, "prod")
+	os.Setenv("APP_NUMBER", "5")
+	defer os.Unsetenv(ProfileEnv)
+	defer os.Unsetenv("APP_NUMBER")
+	env := decoders.NewEnvDecoderOptions(decoders.EnvOptions{
+		Prefix: "APP_",
+		Unused: decoders.FailUnused,
+	})
+	actual = Cfg{}
+	f = NewProfileFlow(&actual, base, "", env)
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Config).Should(Equal(&Cfg{Number: 5, Str1: "base", Str2: "base"}))
+
+	actual = Cfg{}
+	f = NewFlow(&actual, append(ProfileDecoders(base, ""), env)...)
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Config).Should(Equal(&Cfg{Number: 5, Str1: "base", Str2: "base"}))
+
+	os.Setenv("APP_NUMBR", "6")
+	defer os.Unsetenv("APP_NUMBR")
+	Ω(f.LoadFailIfError()).Should(MatchError(ContainSubstring(
+		"unused env vars: APP_NUMBR (did you mean APP_NUMBER?)")))
+}
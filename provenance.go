@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/PlanitarInc/go-config/decoders"
+)
+
+// Origin describes where the value of a config key came from.
+type Origin struct {
+	// Decoder is the decoder that last set the key. It is nil if the key
+	// still holds the value given to NewFlow.
+	Decoder decoders.Decoder
+	// Source is the raw source the decoder read the value from, e.g. a file
+	// path, an env var name or a struct key. It is empty if the decoder does
+	// not implement decoders.Sourcer.
+	Source string
+}
+
+// Provenance returns the origin of every config key as of the last load. The
+// keys are the Go field names of the config struct, nested fields are joined
+// with a dot, e.g. "Server.Port".
+//
+// A decoder is considered to have set a key if it reported setting it, even
+// to the value the key already had (see decoders.WithSetFields), or if the
+// value of the key changed while the decoder was running.
+func (f *Flow) Provenance() map[string]Origin {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	r := make(map[string]Origin, len(f.provenance))
+	for k, o := range f.provenance {
+		r[k] = o
+	}
+	return r
+}
+
+// values returns a copy of the value of every key of cfg.
+func (f *Flow) values(cfg interface{}) map[string]reflect.Value {
+	m := f.fieldMap(reflect.ValueOf(cfg))
+	for key, v := range m {
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		m[key] = c
+	}
+	return m
+}
+
+// track records d as the origin of every key of cfg that d reported to set,
+// i.e. the fields at the indexes in set, or whose value differs from the one
+// in before.
+func (f *Flow) track(prov map[string]Origin, cfg interface{},
+	d decoders.Decoder, before map[string]reflect.Value, set [][]int) {
+
+	v := reflect.ValueOf(cfg)
+	tm := f.typeMap(reflect.Indirect(v).Type())
+	reported := map[string]bool{}
+	for _, index := range set {
+		reported[fmt.Sprint(index)] = true
+	}
+	for key, field := range f.fieldMap(v) {
+		if old, ok := before[key]; ok && !reported[fmt.Sprint(tm[key])] &&
+			reflect.DeepEqual(old.Interface(), field.Interface()) {
+			continue
+		}
+		o := Origin{Decoder: d}
+		if s, ok := d.(decoders.Sourcer); ok {
+			o.Source = s.Source(cfg, tm[key])
+		}
+		prov[key] = o
+	}
+}
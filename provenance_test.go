@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+func TestProvenance(t *testing.T) {
+	RegisterTestingT(t)
+
+	yd := decoders.NewYamlFileDecoder("test.yml")
+	jd := decoders.NewJsonFileDecoder("test.json")
+	ed := decoders.NewEnvDecoder("")
+
+	os.Unsetenv("STR2")
+	os.Setenv("NUMBER", "-987")
+	os.Setenv("NESTED_N", "5")
+	defer os.Unsetenv("NUMBER")
+	defer os.Unsetenv("NESTED_N")
+
+	actual := provCfg{Number: -123, Flag: true, Str1: "qwe", Str2: "asd"}
+	f := NewFlow(&actual, yd, jd, ed)
+	Ω(f.Provenance()).Should(BeEmpty())
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Provenance()).Should(Equal(map[string]Origin{
+		"Number":   {Decoder: ed, Source: "NUMBER"},
+		"Flag":     {Decoder: jd, Source: "test.json"},
+		"Str1":     {Decoder: yd, Source: "test.yml"},
+		"Str2":     {},
+		"Nested.N": {Decoder: ed, Source: "NESTED_N"},
+	}))
+
+	// Decoders that do not implement Sourcer leave the source empty
+	cd := &CountDecoder{}
+	f = NewFlow(&actual, cd, setDecoder{})
+	Ω(f.Load()).Should(BeEmpty())
+	Ω(f.Provenance()["Str2"]).Should(Equal(Origin{Decoder: setDecoder{}}))
+	Ω(f.Provenance()["Number"]).Should(Equal(Origin{}))
+}
+
+type provCfg struct {
+	Number int
+	Flag   bool
+	Str1   string
+	Str2   string
+	Nested struct {
+		N int
+	}
+}
+
+type setDecoder struct{}
+
+func (d setDecoder) Decode(dst interface{}) error {
+	dst.(*provCfg).Str2 = "set"
+	return nil
+}
@@ -11,6 +11,7 @@ import "sync"
 import (
 	"reflect"
 	"runtime"
+	"strings"
 )
 
 type fieldMap map[string][]int
@@ -77,6 +78,23 @@ func (m *Mapper) FieldMap(v reflect.Value) map[string]reflect.Value {
 	return r
 }
 
+// FieldMapReadOnly is like FieldMap, but does not allocate nil pointers as
+// the values are only going to be read.  Fields behind a nil pointer to an
+// embedded struct are left out.
+func (m *Mapper) FieldMapReadOnly(v reflect.Value) map[string]reflect.Value {
+	v = reflect.Indirect(v)
+	mustBe(v, reflect.Struct)
+
+	r := map[string]reflect.Value{}
+	nm := m.TypeMap(v.Type())
+	for tagName, indexes := range nm {
+		if f := fieldByIndexesNoAlloc(v, indexes); f.IsValid() {
+			r[tagName] = f
+		}
+	}
+	return r
+}
+
 // FieldByName returns a field by the its mapped name as a reflect.Value.
 // Panics if v's Kind is not Struct or v is not Indirectable to a struct Kind.
 // Returns zero Value if the name is not found.
@@ -156,6 +174,21 @@ func FieldByIndexesReadOnly(v reflect.Value, indexes []int) reflect.Value {
 	return v
 }
 
+// fieldByIndexesNoAlloc is like FieldByIndexesReadOnly, but returns the zero
+// Value if the traversal runs into a nil pointer.
+func fieldByIndexesNoAlloc(v reflect.Value, indexes []int) reflect.Value {
+	for _, i := range indexes {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
 // Deref is Indirect for reflect.Types
 func Deref(t reflect.Type) reflect.Type {
 	if t.Kind() == reflect.Ptr {
@@ -164,6 +197,73 @@ func Deref(t reflect.Type) reflect.Type {
 	return t
 }
 
+// Copy returns a deep copy of v.  Pointers, slices, maps and interfaces are
+// followed, unexported struct fields are copied as is.
+func Copy(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	src := reflect.ValueOf(v)
+	dst := reflect.New(src.Type()).Elem()
+	copyValue(dst, src)
+	return dst.Interface()
+}
+
+func copyValue(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		copyValue(p.Elem(), src.Elem())
+		dst.Set(p)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		c := reflect.New(src.Elem().Type()).Elem()
+		copyValue(c, src.Elem())
+		dst.Set(c)
+	case reflect.Struct:
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				copyValue(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			copyValue(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			k := reflect.New(iter.Key().Type()).Elem()
+			copyValue(k, iter.Key())
+			e := reflect.New(iter.Value().Type()).Elem()
+			copyValue(e, iter.Value())
+			m.SetMapIndex(k, e)
+		}
+		dst.Set(m)
+	default:
+		dst.Set(src)
+	}
+}
+
 // -- helpers & utilities --
 
 type Kinder interface {
@@ -221,7 +321,12 @@ func getMapping(t reflect.Type, tagName string, mapFunc func(string) string,
 		for fieldPos := 0; fieldPos < tq.t.NumField(); fieldPos++ {
 			f := tq.t.Field(fieldPos)
 
+			// options following the name, e.g. `json:"name,omitempty"`,
+			// are not a part of the name
 			name := f.Tag.Get(tagName)
+			if i := strings.Index(name, ","); i >= 0 {
+				name = name[:i]
+			}
 			if len(name) == 0 {
 				if mapFunc != nil {
 					name = mapFunc(f.Name)
@@ -117,6 +117,17 @@ func TestMapping(t *testing.T) {
 		t.Errorf("Expecting to ignore `IsAllBlack` field")
 	}
 
+	type TaggedPerson struct {
+		Name  string `db:"full_name,omitempty"`
+		Email string `db:",required"`
+	}
+	mapping = m.TypeMap(reflect.TypeOf(TaggedPerson{}))
+	for _, key := range []string{"full_name", "email"} {
+		if _, ok := mapping[key]; !ok {
+			t.Errorf("Expecting to find key %s in mapping but did not.", key)
+		}
+	}
+
 	type EmbeddedLiteral struct {
 		Embedded struct {
 			Person   string
@@ -274,6 +285,76 @@ func TestReducerNested(t *testing.T) {
 	}
 }
 
+func TestFieldMapReadOnly(t *testing.T) {
+	type Foo struct {
+		A *int
+	}
+	type Bar struct {
+		*Foo
+		B *int
+	}
+
+	m := NewMapper("")
+	b := Bar{}
+	fm := m.FieldMapReadOnly(reflect.ValueOf(&b))
+	if len(fm) != 1 || !fm["B"].IsNil() {
+		t.Errorf("Expecting only a nil B, got %v", fm)
+	}
+	if b.Foo != nil || b.B != nil {
+		t.Errorf("Expecting no allocations, got %+v", b)
+	}
+
+	b.Foo = &Foo{}
+	fm = m.FieldMapReadOnly(reflect.ValueOf(&b))
+	if len(fm) != 2 || !fm["A"].IsNil() {
+		t.Errorf("Expecting a nil A, got %v", fm)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	type Inner struct {
+		N int
+		S []string
+	}
+	type Outer struct {
+		Inner
+		P   *Inner
+		M   map[string]*Inner
+		I   interface{}
+		A   [2]Inner
+		Nil *Inner
+		u   int
+	}
+
+	src := &Outer{
+		Inner: Inner{N: 1, S: []string{"a", "b"}},
+		P:     &Inner{N: 2},
+		M:     map[string]*Inner{"x": {N: 3}},
+		I:     Inner{S: []string{"c"}},
+		A:     [2]Inner{{N: 4}, {S: []string{"d"}}},
+		u:     5,
+	}
+	dst := Copy(src).(*Outer)
+
+	if !reflect.DeepEqual(src, dst) {
+		t.Fatalf("Expecting %+v, got %+v", src, dst)
+	}
+	if src == dst || src.P == dst.P || src.M["x"] == dst.M["x"] {
+		t.Errorf("Expecting pointers to be copied")
+	}
+
+	dst.S[0] = "changed"
+	dst.A[1].S[0] = "changed"
+	dst.I.(Inner).S[0] = "changed"
+	if src.S[0] != "a" || src.A[1].S[0] != "d" || src.I.(Inner).S[0] != "c" {
+		t.Errorf("Expecting slices to be copied, got %+v", src)
+	}
+
+	if Copy(nil) != nil {
+		t.Errorf("Expecting nil")
+	}
+}
+
 type E1 struct {
 	A int
 }
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// MissingKey describes a required config key none of the decoders has set.
+type MissingKey struct {
+	Key string
+	// Sources lists the raw sources the decoders could have read the key
+	// from, e.g. env var names and file paths.
+	Sources []string
+}
+
+// RequiredError lists all the required config keys missing after a load.
+type RequiredError struct {
+	Missing []MissingKey
+}
+
+func (e *RequiredError) Error() string {
+	msgs := make([]string, len(e.Missing))
+	for i, m := range e.Missing {
+		msgs[i] = m.Key
+		if len(m.Sources) > 0 {
+			msgs[i] += " (" + strings.Join(m.Sources, ", ") + ")"
+		}
+	}
+	return "missing required config keys: " + strings.Join(msgs, "; ")
+}
+
+// checkRequired returns an error listing all the keys of cfg tagged as
+// `config:",required"` whose value was not set by any of the decoders.
+func (f *Flow) checkRequired(cfg interface{}, prov map[string]Origin) error {
+	t := reflectx.Deref(reflect.TypeOf(cfg))
+	tm := f.typeMap(t)
+
+	keys := make([]string, 0, len(tm))
+	for key := range tm {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	missing := []MissingKey{}
+	for _, key := range keys {
+		if !hasOption(t.FieldByIndex(tm[key]), "required") ||
+			prov[key].Decoder != nil {
+			continue
+		}
+		missing = append(missing, MissingKey{
+			Key:     key,
+			Sources: f.sources(cfg, tm[key]),
+		})
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	return &RequiredError{Missing: missing}
+}
+
+// sources returns the distinct raw sources the decoders of the flow read the
+// field at index of cfg from.
+func (f *Flow) sources(cfg interface{}, index []int) []string {
+	r := []string{}
+	seen := map[string]bool{}
+	for _, d := range f.decoders {
+		s, ok := d.(decoders.Sourcer)
+		if !ok {
+			continue
+		}
+		if src := s.Source(cfg, index); src != "" && !seen[src] {
+			seen[src] = true
+			r = append(r, src)
+		}
+	}
+	return r
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+func TestRequired(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number   int    `config:",required"`
+		Database string `config:"DB,required"`
+		Str2     string
+		Nested   struct {
+			Token string `config:",required"`
+		}
+	}
+
+	os.Unsetenv("DB")
+	os.Unsetenv("NESTED_TOKEN")
+
+	actual := Cfg{}
+	f := NewFlow(&actual,
+		decoders.NewYamlFileDecoder("test.yml"),
+		decoders.NewEnvDecoder("config"),
+	)
+	err := f.LoadFailIfError()
+	Ω(err).Should(Equal(&RequiredError{Missing: []MissingKey{
+		{Key: "Database", Sources: []string{"test.yml", "DB"}},
+		{Key: "Nested.Token", Sources: []string{"test.yml", "NESTED_TOKEN"}},
+	}}))
+	Ω(err).Should(MatchError("missing required config keys: " +
+		"Database (test.yml, DB); Nested.Token (test.yml, NESTED_TOKEN)"))
+
+	os.Setenv("DB", "postgres://")
+	os.Setenv("NESTED_TOKEN", "xxx")
+	defer os.Unsetenv("DB")
+	defer os.Unsetenv("NESTED_TOKEN")
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Config.(*Cfg).Database).Should(Equal("postgres://"))
+	Ω(f.Config.(*Cfg).Nested.Token).Should(Equal("xxx"))
+}
+
+func TestRequiredSameValue(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Port    int  `config:",required"`
+		Retries int  `config:",required"`
+		Debug   bool `config:",required"`
+	}
+
+	os.Setenv("PORT", "8080")
+	os.Setenv("RETRIES", "0")
+	defer os.Unsetenv("PORT")
+	defer os.Unsetenv("RETRIES")
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "config.yml")
+	Ω(ioutil.WriteFile(filename, []byte("debug: false\n"), 0644)).
+		Should(Succeed())
+
+	// Set to the same value as the default and explicitly set to zero
+	actual := Cfg{Port: 8080}
+	f := NewFlow(&actual,
+		decoders.NewYamlFileDecoder(filename),
+		decoders.NewEnvDecoder(""),
+	)
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	prov := f.Provenance()
+	Ω(decoders.Name(prov["Port"].Decoder)).Should(Equal("env"))
+	Ω(decoders.Name(prov["Retries"].Decoder)).Should(Equal("env"))
+	Ω(decoders.Name(prov["Debug"].Decoder)).Should(Equal("yaml file " +
+		filename))
+
+	os.Unsetenv("RETRIES")
+	Ω(f.LoadFailIfError()).Should(MatchError(
+		"missing required config keys: Retries (" + filename + ", RETRIES)"))
+
+	// Every file format reports the keys it holds
+	for name, content := range map[string]string{
+		"config.json":       `{"port": 8080, "retries": 0, "debug": false}`,
+		"config.toml":       "port = 8080\nretries = 0\ndebug = false\n",
+		"config.hcl":        "port = 8080\nretries = 0\ndebug = false\n",
+		"config.ini":        "port = 8080\nretries = 0\ndebug = false\n",
+		"config.properties": "port=8080\nretries=0\ndebug=false\n",
+	} {
+		filename := filepath.Join(dir, name)
+		Ω(ioutil.WriteFile(filename, []byte(content), 0644)).
+			Should(Succeed())
+		f := NewProfileFlow(&actual, filename, "")
+		Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred(), name)
+	}
+
+	filename = filepath.Join(dir, ".env")
+	Ω(ioutil.WriteFile(filename, []byte("PORT=8080\nRETRIES=0\nDEBUG=false\n"),
+		0644)).Should(Succeed())
+	f = NewFlow(&actual, decoders.NewDotenvFileDecoder(filename))
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+
+	f = NewFlow(&actual, decoders.NewStructDecoder(
+		struct{ Port, Retries int }{Port: 8080}, "", ""))
+	Ω(f.LoadFailIfError()).Should(MatchError(
+		"missing required config keys: Debug (Debug)"))
+}
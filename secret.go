@@ -0,0 +1,30 @@
+package config
+
+import "encoding/json"
+
+// Secret is a string that the library never prints: it formats as Redacted
+// with fmt and marshals as Redacted to JSON and YAML. An empty Secret formats
+// as an empty string. Use string(s) to get the actual value.
+//
+// Plain fields can be marked as secret with the `config:",secret"` tag, but
+// only Secret fields are safe from being printed with fmt.
+type Secret string
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+func (s Secret) GoString() string {
+	return "config.Secret(\"" + s.String() + "\")"
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSecret(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		User     string
+		Password Secret
+		Empty    Secret
+	}
+	c := Cfg{User: "root", Password: "pwd"}
+
+	Ω(fmt.Sprint(c.Password)).Should(Equal(Redacted))
+	Ω(fmt.Sprintf("%s %q", c.Password, c.Password)).
+		Should(Equal(`[REDACTED] "[REDACTED]"`))
+	Ω(fmt.Sprintf("%v", c)).Should(Equal("{root [REDACTED] }"))
+	Ω(fmt.Sprintf("%+v", c)).
+		Should(Equal("{User:root Password:[REDACTED] Empty:}"))
+	Ω(fmt.Sprintf("%#v", c)).Should(Equal(`config.Cfg{User:"root", ` +
+		`Password:config.Secret("[REDACTED]"), Empty:config.Secret("")}`))
+
+	bs, err := json.Marshal(c)
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(string(bs)).Should(Equal(
+		`{"User":"root","Password":"[REDACTED]","Empty":""}`))
+
+	bs, err = yaml.Marshal(c)
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(string(bs)).Should(Equal(
+		"user: root\npassword: '[REDACTED]'\nempty: \"\"\n"))
+
+	Ω(string(c.Password)).Should(Equal("pwd"))
+}
+
+func TestSecretFlow(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Password Secret
+		Pin      int `config:",secret"`
+	}
+
+	os.Setenv("PASSWORD", "pwd")
+	os.Setenv("PIN", "abc")
+	defer os.Unsetenv("PASSWORD")
+	defer os.Unsetenv("PIN")
+
+	actual := Cfg{}
+	f := NewFlow(&actual, decoders.NewEnvDecoder(""))
+	errs := f.Load()
+	Ω(errs).Should(MatchError(
+		"env PIN=[REDACTED]: cannot parse as int for field Pin"))
+	Ω(errs[0].(*LoadError).Value).Should(Equal(Redacted))
+	Ω(string(f.Config.(*Cfg).Password)).Should(Equal("pwd"))
+
+	// File decoders do not echo secret values either
+	os.Unsetenv("PIN")
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"config.yaml": "pin: abc123\n",
+		"config.json": `{"pin": 123456789012345678901234}`,
+		"config.hcl":  "pin = \"abc123\"\n",
+	} {
+		filename := filepath.Join(dir, name)
+		Ω(ioutil.WriteFile(filename, []byte(content), 0644)).
+			Should(Succeed())
+		f := NewProfileFlow(&actual, filename, "")
+		errs := f.Load()
+		Ω(errs).Should(HaveLen(1), name)
+		Ω(errs.Error()).Should(ContainSubstring(Redacted), name)
+		Ω(errs.Error()).ShouldNot(ContainSubstring("123"), name)
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"time"
+
+	"github.com/PlanitarInc/go-config/decoders"
+)
+
+// DecoderState is the outcome of a decoder during a load.
+type DecoderState int
+
+const (
+	// NotRun means the decoder did not run, e.g. because an earlier decoder
+	// failed or the load was cancelled before.
+	NotRun DecoderState = iota
+	// OK means the decoder succeeded.
+	OK
+	// Skipped means the decoder had nothing to decode, see Flow.Skipped.
+	Skipped
+	// Warning means the decoder succeeded with a warning, see Flow.Warnings.
+	Warning
+	// Failed means the decoder failed, or the load was cancelled when it
+	// was about to run.
+	Failed
+)
+
+func (s DecoderState) String() string {
+	switch s {
+	case NotRun:
+		return "not run"
+	case OK:
+		return "ok"
+	case Skipped:
+		return "skipped"
+	case Warning:
+		return "warning"
+	case Failed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// DecoderStatus describes how a decoder of a flow did during a load.
+type DecoderStatus struct {
+	Decoder decoders.Decoder
+	// Name is the name of the decoder, see decoders.Name.
+	Name     string
+	State    DecoderState
+	Duration time.Duration
+	// Err is the error the decoder returned, if any.
+	Err error
+}
+
+// Decoders returns the decoders of the flow in the order they run.
+func (f *Flow) Decoders() []decoders.Decoder {
+	return append([]decoders.Decoder{}, f.decoders...)
+}
+
+// Status returns the status of every decoder of the flow during the last
+// load, in the order they run. Unlike the other results of a load, it is
+// recorded even if LoadFailIfError fails and keeps the current config.
+func (f *Flow) Status() []DecoderStatus {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]DecoderStatus{}, f.status...)
+}
+
+// newStatus returns the initial status of the decoders of the flow.
+func (f *Flow) newStatus() []DecoderStatus {
+	r := make([]DecoderStatus, len(f.decoders))
+	for i, d := range f.decoders {
+		r[i] = DecoderStatus{Decoder: d, Name: decoders.Name(d)}
+	}
+	return r
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+func TestStatus(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+	}
+
+	os.Setenv("APP_NUMBR", "5")
+	defer os.Unsetenv("APP_NUMBR")
+
+	e1 := errors.New("1")
+	yd := decoders.NewYamlFileDecoder("test.yml")
+	od := decoders.NewOptionalYamlFileDecoder("missing.yml")
+	ed := decoders.NewEnvDecoderOptions(decoders.EnvOptions{
+		Prefix: "APP_",
+		Unused: decoders.WarnUnused,
+	})
+	fd := FailDecoder{e1}
+
+	actual := Cfg{}
+	f := NewFlow(&actual, yd, od, ed, fd)
+	Ω(f.Decoders()).Should(Equal([]decoders.Decoder{yd, od, ed, fd}))
+	Ω(f.Status()).Should(BeEmpty())
+
+	f.Load()
+	status := f.Status()
+	Ω(status).Should(HaveLen(4))
+	for i, name := range []string{
+		"yaml file test.yml",
+		"optional yaml file missing.yml",
+		"env prefix APP_",
+		"config.FailDecoder",
+	} {
+		Ω(status[i].Decoder).Should(Equal(f.Decoders()[i]))
+		Ω(status[i].Name).Should(Equal(name))
+	}
+	Ω(status[0].State).Should(Equal(OK))
+	Ω(status[0].Duration).Should(BeNumerically(">", 0))
+	Ω(status[0].Err).ShouldNot(HaveOccurred())
+	Ω(status[1].State).Should(Equal(Skipped))
+	Ω(status[1].Err).Should(MatchError(decoders.ErrSkipped))
+	Ω(status[2].State).Should(Equal(Warning))
+	Ω(status[2].Err).Should(MatchError(
+		"unused env vars: APP_NUMBR (did you mean APP_NUMBER?)"))
+	Ω(status[3].State).Should(Equal(Failed))
+	Ω(status[3].Err).Should(MatchError(e1))
+
+	// The status is recorded even if the config is kept
+	Ω(f.LoadFailIfError()).Should(MatchError(e1))
+	Ω(f.Status()[3].State).Should(Equal(Failed))
+
+	f = NewFlow(&actual, yd, fd)
+	Ω(f.LoadFailIfError()).Should(MatchError(e1))
+	Ω(f.Status()[0].State).Should(Equal(OK))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f.LoadContext(ctx)
+	status = f.Status()
+	Ω(status[0].State).Should(Equal(Failed))
+	Ω(status[0].Err).Should(MatchError(context.Canceled))
+	Ω(status[1].State).Should(Equal(NotRun))
+	Ω(status[1].Err).ShouldNot(HaveOccurred())
+	Ω(status[1].State.String()).Should(Equal("not run"))
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// hasOption reports whether the `config` tag of sf lists opt among the
+// options following the name, e.g. `config:",required"`.
+func hasOption(sf reflect.StructField, opt string) bool {
+	opts := strings.Split(sf.Tag.Get("config"), ",")
+	for _, o := range opts[1:] {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// isSecret reports whether the value of sf must not be printed, see
+// decoders.IsSecret.
+func isSecret(sf reflect.StructField) bool {
+	return decoders.IsSecret(sf)
+}
+
+// secretFields returns a function reporting whether the field at index of cfg
+// is secret, see decoders.WithSecrets.
+func secretFields(cfg interface{}) func(index []int) bool {
+	t := reflectx.Deref(reflect.TypeOf(cfg))
+	return func(index []int) bool {
+		return t.Kind() == reflect.Struct && isSecret(t.FieldByIndex(index))
+	}
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	"github.com/PlanitarInc/go-config/reflectx"
+)
+
+// Usage writes a table describing every config key to w: the env vars the
+// env decoders of the flow read it from, its YAML and JSON paths, its Go
+// type, its default value and the description from its `desc` tag.
+//
+// The default value is taken from the `default` tag if the field has one, and
+// from the defaults given to NewFlow otherwise. The defaults of secret fields
+// are replaced with Redacted. The ENV column is left out if the flow has no
+// env decoders.
+func (f *Flow) Usage(w io.Writer) error {
+	cfg := f.defaults
+	v := reflect.ValueOf(cfg)
+	t := reflectx.Deref(v.Type())
+	tm := f.typeMap(t)
+	fm := f.fieldMap(v)
+
+	yamlMapper := reflectx.NewMapperFunc("yaml", strings.ToLower)
+	yamlMapper.SetReduceFunc(reflectx.DelimiterKeyReducer("."))
+	yamlKeys := keysByIndex(yamlMapper, t)
+	jsonMapper := reflectx.NewMapper("json")
+	jsonMapper.SetReduceFunc(reflectx.DelimiterKeyReducer("."))
+	jsonKeys := keysByIndex(jsonMapper, t)
+
+	keys := make([]string, 0, len(tm))
+	for key := range tm {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rows := [][]string{}
+	hasEnv := false
+	for _, key := range keys {
+		index := tm[key]
+		sf := t.FieldByIndex(index)
+
+		envs := []string{}
+		for _, d := range f.decoders {
+			if name, ok := decoders.EnvVar(d, cfg, index); ok {
+				hasEnv = true
+				if name != "" {
+					envs = append(envs, name)
+				}
+			}
+		}
+
+		rows = append(rows, []string{
+			strings.Join(envs, ", "),
+			yamlKeys[fmt.Sprint(index)],
+			jsonKeys[fmt.Sprint(index)],
+			sf.Type.String(),
+			defaultValue(sf, fm[key]),
+			sf.Tag.Get("desc"),
+		})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	header := []string{"ENV", "YAML", "JSON", "TYPE", "DEFAULT", "DESCRIPTION"}
+	for _, row := range append([][]string{header}, rows...) {
+		if !hasEnv {
+			row = row[1:]
+		}
+		if _, err := fmt.Fprintln(tw, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// keysByIndex returns the keys of the fields of t as mapped by m, indexed by
+// the formatted field index.
+func keysByIndex(m *reflectx.Mapper, t reflect.Type) map[string]string {
+	r := map[string]string{}
+	if t.Kind() != reflect.Struct {
+		return r
+	}
+	for key, index := range m.TypeMap(t) {
+		r[fmt.Sprint(index)] = key
+	}
+	return r
+}
+
+// defaultValue formats the default value of the field sf, whose value in the
+// defaults is v.
+func defaultValue(sf reflect.StructField, v reflect.Value) string {
+	if tag, ok := sf.Tag.Lookup("default"); ok {
+		if isSecret(sf) && tag != "" {
+			return Redacted
+		}
+		return tag
+	}
+
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "nil"
+		}
+		v = v.Elem()
+	}
+	if isSecret(sf) && !v.IsZero() {
+		return Redacted
+	}
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("%q", v.String())
+	}
+	return fmt.Sprint(v.Interface())
+}
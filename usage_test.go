@@ -0,0 +1,50 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+func TestUsage(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number   int           `desc:"just a number"`
+		Timeout  time.Duration `default:"30s" desc:"request timeout"`
+		Password Secret        `desc:"database password"`
+		Server   struct {
+			Host string `yaml:"hostname" json:"hostName" desc:"server host"`
+			Port *int
+		}
+		Hosts []string `env:"HOSTS_LIST"`
+	}
+
+	actual := Cfg{Number: 5, Password: "pwd"}
+	actual.Server.Host = "localhost"
+
+	var buf bytes.Buffer
+	f := NewFlow(&actual, decoders.NewEnvDecoderOptions(decoders.EnvOptions{
+		Tagname: "env",
+		Prefix:  "APP_",
+	}))
+	Ω(f.Usage(&buf)).Should(Succeed())
+	Ω(buf.String()).Should(Equal("" +
+		"ENV              YAML             JSON             TYPE           DEFAULT      DESCRIPTION\n" +
+		"APP_HOSTS_LIST   hosts            Hosts            []string       []           \n" +
+		"APP_NUMBER       number           Number           int            5            just a number\n" +
+		"APP_PASSWORD     password         Password         config.Secret  [REDACTED]   database password\n" +
+		"APP_SERVER_HOST  server.hostname  Server.hostName  string         \"localhost\"  server host\n" +
+		"APP_SERVER_PORT  server.port      Server.Port      *int           nil          \n" +
+		"APP_TIMEOUT      timeout          Timeout          time.Duration  30s          request timeout\n"))
+
+	buf.Reset()
+	f = NewFlow(&actual, decoders.NewYamlFileDecoder("test.yml"))
+	Ω(f.Usage(&buf)).Should(Succeed())
+	Ω(buf.String()).Should(HavePrefix("" +
+		"YAML             JSON             TYPE           DEFAULT      DESCRIPTION\n" +
+		"hosts            Hosts            []string       []           \n"))
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidationError describes a config key whose value violates a rule of its
+// `validate` tag, or an error returned by Validator.Validate of a section.
+type ValidationError struct {
+	// Key is the config key of the field or the section, it is empty for
+	// the config struct itself.
+	Key string
+	// Rule is the violated rule, it is empty for Validator errors.
+	Rule string
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Key == "" {
+		return e.Err.Error()
+	}
+	return e.Key + ": " + e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors lists all the violations found in a config.
+type ValidationErrors []*ValidationError
+
+func (es ValidationErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+// validate checks every key of cfg against the comma separated rules of its
+// `validate` tag:
+//
+//	nonzero    the value must not be the zero value of its type
+//	min=N      numbers must be >= N, strings, slices and maps must have at
+//	           least N elements
+//	max=N      numbers must be <= N, strings, slices and maps must have at
+//	           most N elements
+//	oneof=a|b  the value, formatted with fmt, must be one of the listed ones
+//
+// Nil pointers are only checked by nonzero, fields behind a nil pointer to an
+// embedded struct are not checked at all.
+func (f *Flow) validate(cfg interface{}) ValidationErrors {
+	v := reflect.ValueOf(cfg)
+	t := reflect.Indirect(v).Type()
+	tm := f.typeMap(t)
+	fm := f.fieldMap(v)
+
+	keys := make([]string, 0, len(tm))
+	for key := range tm {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	errs := ValidationErrors{}
+	for _, key := range keys {
+		tag := t.FieldByIndex(tm[key]).Tag.Get("validate")
+		field, ok := fm[key]
+		if tag == "" || !ok {
+			continue
+		}
+		for _, rule := range strings.Split(tag, ",") {
+			if err := checkRule(field, rule); err != nil {
+				errs = append(errs, &ValidationError{
+					Key:  key,
+					Rule: rule,
+					Err:  err,
+				})
+			}
+		}
+	}
+	return errs
+}
+
+func checkRule(v reflect.Value, rule string) error {
+	name, arg := rule, ""
+	if i := strings.Index(rule, "="); i >= 0 {
+		name, arg = rule[:i], rule[i+1:]
+	}
+
+	if name == "nonzero" {
+		if v.IsZero() {
+			return errors.New("must not be zero")
+		}
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch name {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid rule %q", rule)
+		}
+		n, isLen, ok := numeric(v)
+		if !ok {
+			return fmt.Errorf("rule %q does not apply to %s", name, v.Type())
+		}
+		what := "must be"
+		if isLen {
+			what = "length must be"
+		}
+		if name == "min" && n < limit {
+			return fmt.Errorf("%s at least %s", what, arg)
+		}
+		if name == "max" && n > limit {
+			return fmt.Errorf("%s at most %s", what, arg)
+		}
+		return nil
+
+	case "oneof":
+		s := fmt.Sprint(v.Interface())
+		opts := strings.Split(arg, "|")
+		for _, o := range opts {
+			if s == o {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(opts, ", "))
+	}
+
+	return fmt.Errorf("unknown rule %q", rule)
+}
+
+// numeric returns the number rules like min and max compare to: the value
+// itself for numbers and the length for strings, slices and maps.
+func numeric(v reflect.Value) (n float64, isLen bool, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), true, true
+	}
+	return 0, false, false
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+func TestValidate(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Pool  int      `validate:"min=1,max=100"`
+		Level string   `validate:"oneof=debug|info"`
+		Name  string   `validate:"nonzero,max=5"`
+		Ratio float64  `validate:"max=0.5"`
+		Hosts []string `validate:"min=1"`
+		Ptr   *int     `validate:"min=3"`
+		Sub   struct {
+			N    uint `validate:"min=2"`
+			Flag bool `validate:"min=1"`
+		}
+		Bad int `validate:"between=1|2"`
+	}
+
+	actual := Cfg{Pool: 10, Level: "info", Name: "n", Hosts: []string{"a"}}
+	f := NewFlow(&actual)
+	errs := f.validate(&actual)
+	Ω(errs).Should(HaveLen(3))
+	Ω(errs[0].Key).Should(Equal("Bad"))
+	Ω(errs[0].Rule).Should(Equal("between=1|2"))
+	Ω(errs[0].Err).Should(MatchError(`unknown rule "between=1|2"`))
+	Ω(errs[1].Key).Should(Equal("Sub.Flag"))
+	Ω(errs[1].Rule).Should(Equal("min=1"))
+	Ω(errs[1].Err).Should(MatchError(`rule "min" does not apply to bool`))
+	Ω(errs[2].Key).Should(Equal("Sub.N"))
+	Ω(errs[2].Rule).Should(Equal("min=2"))
+	Ω(errs[2].Err).Should(MatchError("must be at least 2"))
+
+	ptr := 2
+	actual = Cfg{
+		Pool:  101,
+		Level: "warn",
+		Name:  "too long",
+		Ratio: 0.6,
+		Ptr:   &ptr,
+	}
+	actual.Sub.N = 2
+	errs = f.validate(&actual)
+	Ω(errs).Should(HaveLen(8))
+	Ω(errs.Error()).Should(Equal("invalid config: " +
+		`Bad: unknown rule "between=1|2"; ` +
+		"Hosts: length must be at least 1; " +
+		"Level: must be one of debug, info; " +
+		"Name: length must be at most 5; " +
+		"Pool: must be at most 100; " +
+		"Ptr: must be at least 3; " +
+		"Ratio: must be at most 0.5; " +
+		`Sub.Flag: rule "min" does not apply to bool`))
+
+	actual = Cfg{}
+	errs = f.validate(&actual)
+	Ω(errs[3].Key).Should(Equal("Name"))
+	Ω(errs[3].Rule).Should(Equal("nonzero"))
+	Ω(errs[3]).Should(MatchError("Name: must not be zero"))
+}
+
+func TestValidateFlow(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int `validate:"min=1"`
+		Str1   string
+	}
+
+	os.Setenv("NUMBER", "-987")
+	defer os.Unsetenv("NUMBER")
+
+	actual := Cfg{Number: 5}
+	f := NewFlow(&actual, decoders.NewEnvDecoder(""))
+	err := f.LoadFailIfError()
+	Ω(err).Should(BeAssignableToTypeOf(ValidationErrors{}))
+	Ω(err.(ValidationErrors)[0].Key).Should(Equal("Number"))
+
+	errs := f.Load()
+	Ω(errs).Should(HaveLen(1))
+	Ω(errs[0]).Should(MatchError("invalid config: Number: must be at least 1"))
+}
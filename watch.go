@@ -0,0 +1,112 @@
+package config
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/PlanitarInc/go-config/decoders"
+)
+
+// DefaultWatchInterval is the interval Watch polls the files at, unless
+// Flow.WatchInterval is set.
+const DefaultWatchInterval = time.Second
+
+// OnReload registers fn to be called by Watch after every successful reload.
+// fn receives the previous and the new value of Flow.Config.
+func (f *Flow) OnReload(fn func(old, new interface{})) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.reloadFns = append(f.reloadFns, fn)
+}
+
+// OnReloadError registers fn to be called by Watch whenever a reload fails.
+// The failed reload leaves Flow.Config intact.
+func (f *Flow) OnReloadError(fn func(err error)) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.errorFns = append(f.errorFns, fn)
+}
+
+// Watch polls the files read by the decoders of the flow (see decoders.Filer)
+// and reloads the config whenever any of them changes since the last load.
+// It blocks until ctx is done and returns ctx.Err().
+//
+// A reload is a LoadFailIfError. Callers that keep reading Flow.Config while
+// watching must do so from the OnReload callbacks, or use Snapshot instead.
+func (f *Flow) Watch(ctx context.Context) error {
+	interval := f.WatchInterval
+	if interval == 0 {
+		interval = DefaultWatchInterval
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	f.mutex.Lock()
+	state := f.files
+	f.mutex.Unlock()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+
+		s := f.stat()
+		if reflect.DeepEqual(s, state) {
+			continue
+		}
+		state = s
+		f.reload(ctx)
+	}
+}
+
+func (f *Flow) reload(ctx context.Context) {
+	r, old, ok := f.load(ctx, true)
+
+	f.mutex.Lock()
+	errorFns, reloadFns := f.errorFns, f.reloadFns
+	f.mutex.Unlock()
+
+	if !ok {
+		for _, fn := range errorFns {
+			fn(r.errs[0])
+		}
+		return
+	}
+	for _, fn := range reloadFns {
+		fn(old, r.cfg)
+	}
+}
+
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime int64
+}
+
+// stat returns the state of every file read by the decoders of the flow.
+func (f *Flow) stat() map[string]fileState {
+	r := map[string]fileState{}
+	for _, d := range f.decoders {
+		fd, ok := d.(decoders.Filer)
+		if !ok {
+			continue
+		}
+		for _, name := range fd.Files() {
+			fi, err := os.Stat(name)
+			if err != nil {
+				r[name] = fileState{}
+				continue
+			}
+			r[name] = fileState{
+				exists:  true,
+				size:    fi.Size(),
+				modTime: fi.ModTime().UnixNano(),
+			}
+		}
+	}
+	return r
+}
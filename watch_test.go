@@ -0,0 +1,68 @@
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PlanitarInc/go-config/decoders"
+	. "github.com/onsi/gomega"
+)
+
+func TestWatch(t *testing.T) {
+	RegisterTestingT(t)
+
+	type Cfg struct {
+		Number int
+		Str1   string
+	}
+
+	filename := filepath.Join(t.TempDir(), "config.yml")
+	// Replace the file atomically, so the watcher never sees it half-written
+	writeFile := func(s string) {
+		tmp := filename + ".tmp"
+		Ω(ioutil.WriteFile(tmp, []byte(s), 0644)).Should(Succeed())
+		Ω(os.Rename(tmp, filename)).Should(Succeed())
+	}
+	writeFile("number: 1\nstr1: one\n")
+
+	actual := Cfg{Number: -1, Str1: "def"}
+	f := NewFlow(&actual, decoders.NewYamlFileDecoder(filename))
+	f.WatchInterval = 10 * time.Millisecond
+	Ω(f.LoadFailIfError()).ShouldNot(HaveOccurred())
+	Ω(f.Config).Should(Equal(&Cfg{Number: 1, Str1: "one"}))
+
+	type reload struct{ old, new interface{} }
+	reloads := make(chan reload, 10)
+	f.OnReload(func(old, new interface{}) {
+		reloads <- reload{old, new}
+	})
+	errs := make(chan error, 10)
+	f.OnReloadError(func(err error) {
+		errs <- err
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- f.Watch(ctx) }()
+
+	// Removed keys revert to the defaults
+	writeFile("number: 22\n")
+	var r reload
+	Eventually(reloads).Should(Receive(&r))
+	Ω(r.old).Should(Equal(&Cfg{Number: 1, Str1: "one"}))
+	Ω(r.new).Should(Equal(&Cfg{Number: 22, Str1: "def"}))
+
+	// A broken file keeps the current config
+	writeFile("number: [broken\n")
+	Eventually(errs).Should(Receive(HaveOccurred()))
+	Consistently(reloads, 50*time.Millisecond).ShouldNot(Receive())
+
+	cancel()
+	Eventually(done).Should(Receive(Equal(context.Canceled)))
+	Ω(f.Config).Should(Equal(&Cfg{Number: 22, Str1: "def"}))
+	Ω(actual).Should(Equal(Cfg{Number: -1, Str1: "def"}))
+}